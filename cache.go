@@ -1,6 +1,7 @@
 package dataloader
 
 import (
+	"container/list"
 	"context"
 )
 
@@ -13,6 +14,48 @@ type CacheMap[C comparable, V any] interface {
 	Clear(ctx context.Context) error
 }
 
+// BatchCacheMap is an optional extension of CacheMap for caches that can read
+// and write many keys in one round trip, such as a remote cache. When the
+// loader's cache implements it, lookups are collected for the batch window and
+// resolved with a single GetMany before the misses reach BatchLoadFn. GetMany
+// is called without holding the loader's lock, so implementations must be safe
+// for concurrent use.
+type BatchCacheMap[C comparable, V any] interface {
+	CacheMap[C, V]
+	// GetMany returns the cached values in the same order as keys, using the
+	// zero value for missing keys.
+	GetMany(ctx context.Context, keys []C) ([]V, error)
+	// SetMany stores vals under the keys at the same index.
+	SetMany(ctx context.Context, keys []C, vals []V) error
+}
+
+// TaggedCacheMap is an optional extension of CacheMap for caches that can
+// group entries under tags, so every entry related to one entity can be
+// removed at once with ClearTag.
+type TaggedCacheMap[C comparable, V any] interface {
+	CacheMap[C, V]
+	// SetTags attaches tags to an existing entry.
+	SetTags(ctx context.Context, key C, tags []string) error
+	// DeleteTag removes every entry carrying tag.
+	DeleteTag(ctx context.Context, tag string) error
+}
+
+// IterableCacheMap is an optional extension of CacheMap for caches that can
+// enumerate their entries. It backs ClearFunc and cache snapshots.
+type IterableCacheMap[C comparable, V any] interface {
+	CacheMap[C, V]
+	// Range calls fn for each entry and stops early when fn returns false.
+	// fn must not modify the cache.
+	Range(ctx context.Context, fn func(key C, val V) bool) error
+}
+
+// SizedCacheMap is an optional extension of CacheMap for caches that can count
+// their entries. It backs the cache size of loader stats.
+type SizedCacheMap[C comparable, V any] interface {
+	CacheMap[C, V]
+	Len(ctx context.Context) (int, error)
+}
+
 type NoCache[C comparable, V any] struct{}
 
 func NewNoCache[C comparable, V any]() *NoCache[C, V]                { return &NoCache[C, V]{} }
@@ -23,11 +66,17 @@ func (c *NoCache[C, V]) Clear(ctx context.Context) error             { return ni
 
 type InMemoryCache[C comparable, V any] struct {
 	items map[C]V
+	tags  map[string]map[C]struct{}
+	// keyTags indexes the tags of each key, so deleting a key also removes it
+	// from its tags.
+	keyTags map[C][]string
 }
 
 func NewInMemoryCache[C comparable, V any]() *InMemoryCache[C, V] {
 	return &InMemoryCache[C, V]{
-		items: make(map[C]V),
+		items:   make(map[C]V),
+		tags:    make(map[string]map[C]struct{}),
+		keyTags: make(map[C][]string),
 	}
 }
 
@@ -36,16 +85,146 @@ func (c *InMemoryCache[C, V]) Get(ctx context.Context, key C) (V, error) {
 }
 
 func (c *InMemoryCache[C, V]) Set(ctx context.Context, key C, val V) error {
+	c.untag(key)
 	c.items[key] = val
 	return nil
 }
 
 func (c *InMemoryCache[C, V]) Delete(ctx context.Context, key C) error {
 	delete(c.items, key)
+	c.untag(key)
 	return nil
 }
 
+// untag removes key from its tags, as the tags belong to the value being
+// replaced or deleted.
+func (c *InMemoryCache[C, V]) untag(key C) {
+	for _, tag := range c.keyTags[key] {
+		delete(c.tags[tag], key)
+		if len(c.tags[tag]) == 0 {
+			delete(c.tags, tag)
+		}
+	}
+	delete(c.keyTags, key)
+}
+
 func (c *InMemoryCache[C, V]) Clear(ctx context.Context) error {
 	c.items = make(map[C]V)
+	c.tags = make(map[string]map[C]struct{})
+	c.keyTags = make(map[C][]string)
+	return nil
+}
+
+func (c *InMemoryCache[C, V]) SetTags(ctx context.Context, key C, tags []string) error {
+	if _, ok := c.items[key]; !ok {
+		return nil
+	}
+
+	for _, tag := range tags {
+		if _, ok := c.tags[tag][key]; ok {
+			continue
+		}
+		if c.tags[tag] == nil {
+			c.tags[tag] = make(map[C]struct{})
+		}
+		c.tags[tag][key] = struct{}{}
+		c.keyTags[key] = append(c.keyTags[key], tag)
+	}
+	return nil
+}
+
+func (c *InMemoryCache[C, V]) DeleteTag(ctx context.Context, tag string) error {
+	for key := range c.tags[tag] {
+		c.Delete(ctx, key)
+	}
+	return nil
+}
+
+func (c *InMemoryCache[C, V]) Range(ctx context.Context, fn func(key C, val V) bool) error {
+	for key, val := range c.items {
+		if !fn(key, val) {
+			break
+		}
+	}
+	return nil
+}
+
+func (c *InMemoryCache[C, V]) Len(ctx context.Context) (int, error) {
+	return len(c.items), nil
+}
+
+// LRUCache is a CacheMap holding at most size entries. When it is full, the
+// least recently used entry is evicted to make room for a new one.
+type LRUCache[C comparable, V any] struct {
+	size  int
+	order *list.List
+	items map[C]*list.Element
+}
+
+type lruEntry[C comparable, V any] struct {
+	key C
+	val V
+}
+
+func NewLRUCache[C comparable, V any](size int) *LRUCache[C, V] {
+	return &LRUCache[C, V]{
+		size:  size,
+		order: list.New(),
+		items: make(map[C]*list.Element),
+	}
+}
+
+func (c *LRUCache[C, V]) Get(ctx context.Context, key C) (V, error) {
+	elem, ok := c.items[key]
+	if !ok {
+		return *new(V), nil
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*lruEntry[C, V]).val, nil
+}
+
+func (c *LRUCache[C, V]) Set(ctx context.Context, key C, val V) error {
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*lruEntry[C, V]).val = val
+		c.order.MoveToFront(elem)
+		return nil
+	}
+
+	c.items[key] = c.order.PushFront(&lruEntry[C, V]{key: key, val: val})
+	for c.order.Len() > c.size && c.order.Len() > 0 {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry[C, V]).key)
+	}
+	return nil
+}
+
+func (c *LRUCache[C, V]) Delete(ctx context.Context, key C) error {
+	if elem, ok := c.items[key]; ok {
+		c.order.Remove(elem)
+		delete(c.items, key)
+	}
+	return nil
+}
+
+func (c *LRUCache[C, V]) Clear(ctx context.Context) error {
+	c.order.Init()
+	c.items = make(map[C]*list.Element)
 	return nil
 }
+
+// Range calls fn for each entry from the most to the least recently used,
+// without changing the order. It stops early when fn returns false.
+func (c *LRUCache[C, V]) Range(ctx context.Context, fn func(key C, val V) bool) error {
+	for elem := c.order.Front(); elem != nil; elem = elem.Next() {
+		entry := elem.Value.(*lruEntry[C, V])
+		if !fn(entry.key, entry.val) {
+			break
+		}
+	}
+	return nil
+}
+
+func (c *LRUCache[C, V]) Len(ctx context.Context) (int, error) {
+	return c.order.Len(), nil
+}
@@ -106,3 +106,149 @@ var _ = Describe("InMemoryCache", func() {
 		Expect(err).To(BeNil())
 	})
 })
+
+var _ = Describe("InMemoryCache range", func() {
+	It("visit every entry", func() {
+		ctx := context.TODO()
+		cache := NewInMemoryCache[string, string]()
+		cache.Set(ctx, "foo", "1")
+		cache.Set(ctx, "bar", "2")
+
+		visited := map[string]string{}
+		err := cache.Range(ctx, func(key string, val string) bool {
+			visited[key] = val
+			return true
+		})
+		Expect(err).To(BeNil())
+		Expect(visited).To(Equal(map[string]string{"foo": "1", "bar": "2"}))
+	})
+
+	It("stop when function returns false", func() {
+		ctx := context.TODO()
+		cache := NewInMemoryCache[string, string]()
+		cache.Set(ctx, "foo", "1")
+		cache.Set(ctx, "bar", "2")
+
+		count := 0
+		cache.Range(ctx, func(string, string) bool {
+			count += 1
+			return false
+		})
+		Expect(count).To(Equal(1))
+	})
+})
+
+var _ = Describe("InMemoryCache tags", func() {
+	It("delete every key with tag", func() {
+		ctx := context.TODO()
+		cache := NewInMemoryCache[string, string]()
+		cache.Set(ctx, "post:1", "a")
+		cache.Set(ctx, "post:2", "b")
+		cache.Set(ctx, "post:3", "c")
+		cache.SetTags(ctx, "post:1", []string{"user:42"})
+		cache.SetTags(ctx, "post:2", []string{"user:42", "user:7"})
+		cache.SetTags(ctx, "post:3", []string{"user:7"})
+
+		Expect(cache.DeleteTag(ctx, "user:42")).To(Succeed())
+		Expect(cache.items).To(Equal(map[string]string{"post:3": "c"}))
+		Expect(cache.tags).To(Equal(map[string]map[string]struct{}{"user:7": {"post:3": {}}}))
+	})
+
+	It("ignore tags for missing key", func() {
+		ctx := context.TODO()
+		cache := NewInMemoryCache[string, string]()
+		Expect(cache.SetTags(ctx, "foo", []string{"bar"})).To(Succeed())
+		Expect(cache.tags).To(BeEmpty())
+	})
+
+	It("forget tags of deleted key", func() {
+		ctx := context.TODO()
+		cache := NewInMemoryCache[string, string]()
+		cache.Set(ctx, "foo", "1")
+		cache.SetTags(ctx, "foo", []string{"bar"})
+		cache.Delete(ctx, "foo")
+		cache.Set(ctx, "foo", "2")
+		cache.DeleteTag(ctx, "bar")
+
+		val, _ := cache.Get(ctx, "foo")
+		Expect(val).To(Equal("2"))
+	})
+
+	It("forget tags of overwritten key", func() {
+		ctx := context.TODO()
+		cache := NewInMemoryCache[string, string]()
+		cache.Set(ctx, "foo", "1")
+		cache.SetTags(ctx, "foo", []string{"bar"})
+		cache.Set(ctx, "foo", "2")
+		cache.DeleteTag(ctx, "bar")
+
+		val, _ := cache.Get(ctx, "foo")
+		Expect(val).To(Equal("2"))
+		Expect(cache.tags).To(BeEmpty())
+	})
+})
+
+var _ = Describe("LRUCache", func() {
+	It("get correct value if set", func() {
+		ctx := context.TODO()
+		cache := NewLRUCache[string, string](2)
+		err := cache.Set(ctx, "foo", "bar")
+		Expect(err).To(BeNil())
+
+		val, err := cache.Get(ctx, "foo")
+		Expect(val).To(Equal("bar"))
+		Expect(err).To(BeNil())
+	})
+
+	It("evict least recently used entry when full", func() {
+		ctx := context.TODO()
+		cache := NewLRUCache[string, string](2)
+		cache.Set(ctx, "foo", "1")
+		cache.Set(ctx, "bar", "2")
+		cache.Get(ctx, "foo")
+		cache.Set(ctx, "baz", "3")
+
+		v1, _ := cache.Get(ctx, "foo")
+		Expect(v1).To(Equal("1"))
+		v2, _ := cache.Get(ctx, "bar")
+		Expect(v2).To(Equal(""))
+		v3, _ := cache.Get(ctx, "baz")
+		Expect(v3).To(Equal("3"))
+
+		size, err := cache.Len(ctx)
+		Expect(err).To(BeNil())
+		Expect(size).To(Equal(2))
+	})
+
+	It("get zero value after delete and clear", func() {
+		ctx := context.TODO()
+		cache := NewLRUCache[string, string](2)
+		cache.Set(ctx, "foo", "1")
+		cache.Set(ctx, "bar", "2")
+
+		cache.Delete(ctx, "foo")
+		v1, _ := cache.Get(ctx, "foo")
+		Expect(v1).To(Equal(""))
+
+		cache.Clear(ctx)
+		v2, _ := cache.Get(ctx, "bar")
+		Expect(v2).To(Equal(""))
+	})
+
+	It("range from most to least recently used", func() {
+		ctx := context.TODO()
+		cache := NewLRUCache[string, string](3)
+		cache.Set(ctx, "foo", "1")
+		cache.Set(ctx, "bar", "2")
+		cache.Set(ctx, "baz", "3")
+		cache.Get(ctx, "foo")
+
+		keys := []string{}
+		err := cache.Range(ctx, func(key string, _ string) bool {
+			keys = append(keys, key)
+			return true
+		})
+		Expect(err).To(BeNil())
+		Expect(keys).To(Equal([]string{"foo", "baz", "bar"}))
+	})
+})
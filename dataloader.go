@@ -2,6 +2,10 @@ package dataloader
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -11,14 +15,24 @@ type DataLoader[K any, V any, C comparable] interface {
 	Clear(context.Context, K) DataLoader[K, V, C]
 	ClearAll(ctx context.Context) DataLoader[K, V, C]
 	Prime(context.Context, K, V) DataLoader[K, V, C]
+	PrimeWithTags(context.Context, K, V, ...string) DataLoader[K, V, C]
+	ClearTag(context.Context, string) DataLoader[K, V, C]
+	ClearFunc(context.Context, func(C) bool) DataLoader[K, V, C]
 	Dispatch()
 }
 
 type Result[V any] struct {
 	Value V
 	Error error
+	// Tags are attached to the cached entry when the cache map is a
+	// TaggedCacheMap, so it can be cleared with ClearTag.
+	Tags []string
 }
 
+// ErrBatchResultLength is the error of every key of a batch whose batch load
+// function returned a different number of results than keys.
+var ErrBatchResultLength = errors.New("batch load function returned a different number of results than keys")
+
 type BatchLoadFn[K any, V any] func(context.Context, []K) []Result[V]
 type BatchScheduleFn func(ctx context.Context, batch Batch, callback func())
 type CacheKeyFn[K any, C comparable] func(ctx context.Context, key K) (C, error)
@@ -26,76 +40,188 @@ type CacheKeyFn[K any, C comparable] func(ctx context.Context, key K) (C, error)
 func New[K any, V any, C comparable](ctx context.Context, batchLoadFn BatchLoadFn[K, V], options ...option[K, V, C]) DataLoader[K, V, C] {
 	l := &loader[K, V, C]{
 		ctx:             ctx,
-		batches:         make(chan []*batch[K, V], 1),
+		batches:         make(chan []*batch[K, V, C], 1),
 		batchLoadFn:     batchLoadFn,
-		batchScheduleFn: NewTimeWindowScheduler(16 * time.Millisecond),
+		batchScheduleFn: nil,
 		hook:            nil,
 		cacheKeyFn:      NewMirrorCacheKey[K, C](),
 		cacheMap:        make(chan CacheMap[C, *Thunk[V]], 1),
 		maxBatchSize:    100,
+		pending:         make(map[C]*Thunk[V]),
+		clock:           SystemClock,
+		stats:           make(chan *Stats, 1),
+		closers:         make(chan []func(), 1),
+
+		priorityScheduleFns: make(map[Priority]BatchScheduleFn),
 	}
 
 	l.cacheMap <- NewInMemoryCache[C, *Thunk[V]]()
-	l.batches <- []*batch[K, V]{}
+	l.batches <- []*batch[K, V, C]{}
+	l.stats <- &Stats{}
+	l.closers <- nil
 
 	for _, option := range options {
 		option(l)
 	}
 
+	// High and low priority batches get their own windows unless the loader
+	// was given a scheduler, which then schedules every priority.
+	if l.batchScheduleFn == nil {
+		l.batchScheduleFn = NewTimeWindowScheduler(16 * time.Millisecond)
+		if l.batchScheduler == nil {
+			for priority, scheduleFn := range defaultPriorityScheduleFns() {
+				if _, ok := l.priorityScheduleFns[priority]; !ok {
+					l.priorityScheduleFns[priority] = scheduleFn
+				}
+			}
+		}
+	}
+
 	return l
 }
 
 type loader[K any, V any, C comparable] struct {
 	ctx             context.Context
-	batches         chan []*batch[K, V]
+	name            string
+	batches         chan []*batch[K, V, C]
 	batchLoadFn     BatchLoadFn[K, V]
 	batchScheduleFn BatchScheduleFn
+	batchScheduler  BatchScheduler
 	hook            Hook[K, V]
 	cacheKeyFn      CacheKeyFn[K, C]
 	cacheMap        chan CacheMap[C, *Thunk[V]]
 	maxBatchSize    int
+	invalidator     Invalidator[C]
+	clock           Clock
+	stats           chan *Stats
+	// pending holds thunks waiting for a batched cache lookup, keyed by cache
+	// key. It is only used with a BatchCacheMap and shares the cacheMap lock.
+	pending map[C]*Thunk[V]
+	// priorityScheduleFns replaces the batch schedule function for batches
+	// of the given priorities.
+	priorityScheduleFns map[Priority]BatchScheduleFn
+	partitionFn         func(K) string
+	nPlusOne            *nPlusOneDetector
+	// closers release what the options registered the loader with, see
+	// Close.
+	closers chan []func()
 }
 
-type batch[K any, V any] struct {
-	full     chan struct{}
-	dispatch chan struct{}
-	keys     []K
-	thunks   []*Thunk[V]
+type batch[K any, V any, C comparable] struct {
+	full      chan struct{}
+	dispatch  chan struct{}
+	changed   chan struct{}
+	waited    chan struct{}
+	waitOnce  sync.Once
+	keys      []K
+	cacheKeys []C
+	thunks    []*Thunk[V]
+	ctxs      []context.Context
+	// size mirrors len(keys) for schedulers reading it without the lock.
+	size      int32
+	maxSize   int
+	createdAt time.Time
+	priority  Priority
+	partition string
+	// stack is the stack of the Load call which created the batch, captured
+	// for the N+1 detector.
+	stack []uintptr
 }
 
-func (b *batch[K, V]) Full() <-chan struct{} {
+func (b *batch[K, V, C]) Full() <-chan struct{} {
 	return b.full
 }
 
-func (b *batch[K, V]) Dispatch() <-chan struct{} {
+func (b *batch[K, V, C]) Dispatch() <-chan struct{} {
 	return b.dispatch
 }
 
+func (b *batch[K, V, C]) Changed() <-chan struct{} {
+	return b.changed
+}
+
+func (b *batch[K, V, C]) Waited() <-chan struct{} {
+	return b.waited
+}
+
+func (b *batch[K, V, C]) wait() {
+	b.waitOnce.Do(func() {
+		close(b.waited)
+	})
+}
+
+func (b *batch[K, V, C]) Len() int {
+	return int(atomic.LoadInt32(&b.size))
+}
+
+func (b *batch[K, V, C]) MaxSize() int {
+	return b.maxSize
+}
+
+func (b *batch[K, V, C]) CreatedAt() time.Time {
+	return b.createdAt
+}
+
 type Batch interface {
 	Full() <-chan struct{}
 	Dispatch() <-chan struct{}
+	// Changed receives a value after keys are appended to the batch.
+	// Notifications are coalesced, so one receive may cover several keys.
+	Changed() <-chan struct{}
+	// Waited is closed when Thunk.Get is first called on a pending thunk of
+	// the batch.
+	Waited() <-chan struct{}
+	// Len is the number of keys currently in the batch.
+	Len() int
+	// MaxSize is the number of keys at which the batch is full.
+	MaxSize() int
+	// CreatedAt is when the first key was added to the batch.
+	CreatedAt() time.Time
 }
 
 func (l *loader[K, V, C]) Load(ctx context.Context, key K) *Thunk[V] {
+	ctx = l.named(ctx)
+	if hook, ok := l.hook.(LoadHook[K]); ok {
+		hook.OnLoad(ctx, key)
+	}
+	l.count(func(stats *Stats) { stats.Loads++ })
+
 	cacheKey, err := l.cacheKeyFn(ctx, key)
 	if err != nil {
 		thunk := NewThunk[V]()
-		thunk.error(ctx, err)
+		thunk.error(ctx, l.wrap(err))
 		return thunk
 	}
 
 	cacheMap := <-l.cacheMap
+
+	if _, ok := cacheMap.(BatchCacheMap[C, *Thunk[V]]); ok {
+		if pending, ok := l.pending[cacheKey]; ok {
+			l.cacheMap <- cacheMap
+			l.cacheHit(ctx, key)
+			return pending
+		}
+
+		thunk := NewThunk[V]()
+		l.pending[cacheKey] = thunk
+		l.cacheMap <- cacheMap
+
+		l.enqueue(ctx, key, cacheKey, thunk)
+		return thunk
+	}
+
 	cached, err := cacheMap.Get(ctx, cacheKey)
 
 	if err != nil {
 		l.cacheMap <- cacheMap
 		thunk := NewThunk[V]()
-		thunk.error(ctx, err)
+		thunk.error(ctx, l.wrap(err))
 		return thunk
 	}
 
 	if cached != nil {
 		l.cacheMap <- cacheMap
+		l.cacheHit(ctx, key)
 		return cached
 	}
 
@@ -103,38 +229,101 @@ func (l *loader[K, V, C]) Load(ctx context.Context, key K) *Thunk[V] {
 	err = cacheMap.Set(ctx, cacheKey, thunk)
 	if err != nil {
 		l.cacheMap <- cacheMap
-		thunk.error(ctx, err)
+		thunk.error(ctx, l.wrap(err))
 		return thunk
 	}
 
 	l.cacheMap <- cacheMap
+	l.cacheMiss(ctx, key)
+
+	l.enqueue(ctx, key, cacheKey, thunk)
+	return thunk
+}
+
+// enqueue adds a key to the open batch of its partition and of the priority
+// carried by ctx, or to a new batch if there is none or it is full.
+func (l *loader[K, V, C]) enqueue(ctx context.Context, key K, cacheKey C, thunk *Thunk[V]) {
+	priority := PriorityFromContext(ctx)
+	partition := ""
+	if l.partitionFn != nil {
+		partition = l.partitionFn(key)
+	}
 
 	batches := <-l.batches
 
-	if len(batches) == 0 || len(batches[len(batches)-1].keys) >= l.maxBatchSize {
-		b := &batch[K, V]{
-			full:     make(chan struct{}),
-			dispatch: make(chan struct{}),
-			keys:     []K{},
-			thunks:   []*Thunk[V]{},
+	var bat, created *batch[K, V, C]
+	for index := len(batches) - 1; index >= 0; index-- {
+		if batches[index].priority == priority && batches[index].partition == partition {
+			bat = batches[index]
+			break
+		}
+	}
+
+	if bat == nil || len(bat.keys) >= l.maxBatchSize {
+		bat = &batch[K, V, C]{
+			full:      make(chan struct{}),
+			dispatch:  make(chan struct{}),
+			changed:   make(chan struct{}, 1),
+			waited:    make(chan struct{}),
+			keys:      []K{},
+			cacheKeys: []C{},
+			thunks:    []*Thunk[V]{},
+			maxSize:   l.maxBatchSize,
+			createdAt: l.clock.Now(),
+			priority:  priority,
+			partition: partition,
 		}
 
-		batches = append(batches, b)
+		if l.nPlusOne != nil {
+			bat.stack = callers(2)
+		}
 
-		go l.batchScheduleFn(l.ctx, b, l.dispatch)
+		batches = append(batches, bat)
+		created = bat
 	}
 
-	bat := batches[len(batches)-1]
 	bat.keys = append(bat.keys, key)
+	bat.cacheKeys = append(bat.cacheKeys, cacheKey)
 	bat.thunks = append(bat.thunks, thunk)
+	bat.ctxs = append(bat.ctxs, ctx)
+	thunk.waited.Store(bat.wait)
+	atomic.StoreInt32(&bat.size, int32(len(bat.keys)))
+
+	select {
+	case bat.changed <- struct{}{}:
+	default:
+	}
 
-	if len(batches) != 0 && len(batches[len(batches)-1].keys) >= l.maxBatchSize {
-		close(batches[len(batches)-1].full)
+	full := len(bat.keys) >= l.maxBatchSize
+	if full {
+		close(bat.full)
 	}
 
 	l.batches <- batches
 
-	return thunk
+	if created != nil {
+		if hook, ok := l.hook.(BatchHook); ok {
+			hook.OnBatchCreated(l.ctx, created)
+		}
+		l.schedule(created)
+	}
+	if full {
+		l.wake()
+	}
+}
+
+func (l *loader[K, V, C]) schedule(b *batch[K, V, C]) {
+	callback := func() {
+		l.dispatchBatch(b)
+	}
+
+	if scheduleFn, ok := l.priorityScheduleFns[b.priority]; ok {
+		go scheduleFn(l.ctx, b, callback)
+	} else if l.batchScheduler != nil {
+		l.batchScheduler.Schedule(l.ctx, b, callback)
+	} else {
+		go l.batchScheduleFn(l.ctx, b, callback)
+	}
 }
 
 func (l *loader[K, V, C]) LoadMany(ctx context.Context, keys []K) []*Thunk[V] {
@@ -147,6 +336,7 @@ func (l *loader[K, V, C]) LoadMany(ctx context.Context, keys []K) []*Thunk[V] {
 }
 
 func (l *loader[K, V, C]) Dispatch() {
+	dispatched := false
 	batches := <-l.batches
 	for _, batch := range batches {
 		select {
@@ -154,13 +344,44 @@ func (l *loader[K, V, C]) Dispatch() {
 		case <-batch.dispatch:
 		default:
 			close(batch.dispatch)
+			dispatched = true
 		}
 	}
 	l.batches <- batches
+
+	if dispatched {
+		l.wake()
+	}
+}
+
+// Close unsubscribes the loader from its invalidator and removes it from its
+// dispatch group. Every loader implements io.Closer; loaders living as long as
+// their context do not need to be closed. Close always returns nil.
+func (l *loader[K, V, C]) Close() error {
+	closers := <-l.closers
+	l.closers <- nil
+
+	for _, closer := range closers {
+		closer()
+	}
+	return nil
 }
 
+// onClose registers closer to be called by Close.
+func (l *loader[K, V, C]) onClose(closer func()) {
+	closers := <-l.closers
+	l.closers <- append(closers, closer)
+}
+
+// wake tells a polling batch scheduler that a batch is ready.
+func (l *loader[K, V, C]) wake() {
+	if waker, ok := l.batchScheduler.(BatchWaker); ok {
+		waker.Wake()
+	}
+}
+
+// dispatch executes the oldest pending batch.
 func (l *loader[K, V, C]) dispatch() {
-	ctx := l.ctx
 	batches := <-l.batches
 
 	if len(batches) == 0 {
@@ -171,39 +392,350 @@ func (l *loader[K, V, C]) dispatch() {
 
 	l.batches <- batches[1:]
 
+	l.execute(batch, dispatchReason(batch))
+}
+
+// dispatchBatch executes b unless it was already executed.
+func (l *loader[K, V, C]) dispatchBatch(b *batch[K, V, C]) {
+	batches := <-l.batches
+
+	for index, batch := range batches {
+		if batch == b {
+			l.batches <- append(batches[:index:index], batches[index+1:]...)
+			l.execute(b, dispatchReason(b))
+			return
+		}
+	}
+
+	l.batches <- batches
+}
+
+// dispatchReason tells why a batch is dispatched from the state of its
+// channels. A batch neither full nor manually dispatched was dispatched by its
+// scheduler.
+func dispatchReason[K any, V any, C comparable](b *batch[K, V, C]) DispatchReason {
+	select {
+	case <-b.full:
+		return DispatchFull
+	default:
+	}
+	select {
+	case <-b.dispatch:
+		return DispatchManual
+	default:
+	}
+	return DispatchScheduled
+}
+
+func (l *loader[K, V, C]) execute(batch *batch[K, V, C], reason DispatchReason) {
+	// Thunks outlive their batch in the cache, so drop their references to it.
+	for _, thunk := range batch.thunks {
+		thunk.waited.Store(noWait)
+	}
+	defer func() { batch.ctxs = nil }()
+
+	ctx := ContextWithPriority(l.ctx, batch.priority)
+	ctx = context.WithValue(ctx, loadContextsKey{}, batch.ctxs)
+	ctx = context.WithValue(ctx, batchKey{}, Batch(batch))
+	if l.partitionFn != nil {
+		ctx = ContextWithPartition(ctx, batch.partition)
+	}
+
+	if hook, ok := l.hook.(BatchHook); ok {
+		hook.OnBatchDispatched(ctx, batch, reason)
+	}
+	if l.nPlusOne != nil {
+		l.nPlusOne.observe(l.name, len(batch.keys), batch.stack, l.clock.Now())
+	}
+
+	keys, cacheKeys, thunks := batch.keys, batch.cacheKeys, batch.thunks
+
+	cacheMap := <-l.cacheMap
+	batchCache, useBatchCache := cacheMap.(BatchCacheMap[C, *Thunk[V]])
+	l.cacheMap <- cacheMap
+
+	if useBatchCache {
+		keys, cacheKeys, thunks = l.lookup(ctx, batchCache, batch)
+		if len(keys) == 0 {
+			return
+		}
+	}
+
+	l.count(func(stats *Stats) {
+		stats.Batches++
+		stats.DispatchedKeys += uint64(len(keys))
+		if len(keys) > stats.MaxBatchSize {
+			stats.MaxBatchSize = len(keys)
+		}
+		stats.InFlightBatches++
+	})
+	defer l.count(func(stats *Stats) { stats.InFlightBatches-- })
+
+	ctx = context.WithValue(ctx, batchStartKey{}, l.clock.Now())
 	if l.hook != nil {
-		l.hook.BeforeBatch(ctx, batch.keys)
+		l.hook.BeforeBatch(ctx, keys)
 	}
-	results := l.batchLoadFn(ctx, batch.keys)
+	results := l.batchLoadFn(ctx, keys)
 	if l.hook != nil {
-		l.hook.AfterBatch(ctx, batch.keys, results)
+		l.hook.AfterBatch(ctx, keys, results)
 	}
 
+	if len(results) != len(keys) {
+		err := l.wrap(fmt.Errorf("%w: %d results for %d keys", ErrBatchResultLength, len(results), len(keys)))
+		l.batchError(ctx, keys, err)
+		results = make([]Result[V], len(keys))
+		for index := range results {
+			results[index] = Result[V]{Error: err}
+		}
+	}
+
+	// Cache entries are stored and tagged before the thunks resolve so that
+	// callers returning from Get can already clear them by tag.
+	if useBatchCache {
+		l.store(ctx, batchCache, cacheKeys, thunks, results)
+	}
+	l.tag(ctx, cacheKeys, results)
+
 	for index, res := range results {
 		if res.Error != nil {
-			batch.thunks[index].error(ctx, res.Error)
+			thunks[index].error(ctx, res.Error)
 		} else {
-			batch.thunks[index].set(ctx, res.Value)
+			thunks[index].set(ctx, res.Value)
 		}
 	}
 }
 
+// tag attaches the tags of the results to their cache entries.
+func (l *loader[K, V, C]) tag(ctx context.Context, cacheKeys []C, results []Result[V]) {
+	cacheMap := <-l.cacheMap
+	if tagged, ok := cacheMap.(TaggedCacheMap[C, *Thunk[V]]); ok {
+		for index, res := range results {
+			if res.Error == nil && len(res.Tags) != 0 {
+				tagged.SetTags(ctx, cacheKeys[index], res.Tags)
+			}
+		}
+	}
+	l.cacheMap <- cacheMap
+}
+
+// lookup resolves the keys of a batch found in a BatchCacheMap with a single
+// GetMany call, and returns the keys, cache keys and thunks of the misses.
+func (l *loader[K, V, C]) lookup(ctx context.Context, batchCache BatchCacheMap[C, *Thunk[V]], b *batch[K, V, C]) ([]K, []C, []*Thunk[V]) {
+	// GetMany may be a round trip to a remote cache, so it runs without the
+	// cacheMap lock to let Load and Clear go on meanwhile.
+	cached, err := batchCache.GetMany(ctx, b.cacheKeys)
+
+	if err != nil {
+		err = l.wrap(err)
+		l.batchError(ctx, b.keys, err)
+		for _, thunk := range b.thunks {
+			thunk.error(ctx, err)
+		}
+		l.release(b.cacheKeys, b.thunks)
+		return nil, nil, nil
+	}
+
+	keys := []K{}
+	cacheKeys := []C{}
+	thunks := []*Thunk[V]{}
+	hitKeys := []C{}
+	hitThunks := []*Thunk[V]{}
+
+	for index, thunk := range b.thunks {
+		if index < len(cached) && cached[index] != nil {
+			l.cacheHit(ctx, b.keys[index])
+			val, err := cached[index].Get(ctx)
+			if err != nil {
+				thunk.error(ctx, err)
+			} else {
+				thunk.set(ctx, val)
+			}
+			hitKeys = append(hitKeys, b.cacheKeys[index])
+			hitThunks = append(hitThunks, thunk)
+			continue
+		}
+
+		l.cacheMiss(ctx, b.keys[index])
+		keys = append(keys, b.keys[index])
+		cacheKeys = append(cacheKeys, b.cacheKeys[index])
+		thunks = append(thunks, thunk)
+	}
+
+	l.release(hitKeys, hitThunks)
+
+	return keys, cacheKeys, thunks
+}
+
+// store writes the successfully loaded values of a batch back to a
+// BatchCacheMap with a single SetMany call. Errors are not cached.
+func (l *loader[K, V, C]) store(ctx context.Context, batchCache BatchCacheMap[C, *Thunk[V]], cacheKeys []C, thunks []*Thunk[V], results []Result[V]) {
+	keys := []C{}
+	vals := []*Thunk[V]{}
+
+	// Keys cleared while the batch was in flight are no longer pending and
+	// must not be written back.
+	cacheMap := <-l.cacheMap
+	for index, cacheKey := range cacheKeys {
+		if l.pending[cacheKey] != thunks[index] {
+			continue
+		}
+		delete(l.pending, cacheKey)
+		if results[index].Error == nil {
+			keys = append(keys, cacheKey)
+			vals = append(vals, thunks[index])
+		}
+	}
+	if len(keys) != 0 {
+		batchCache.SetMany(ctx, keys, vals)
+	}
+	l.cacheMap <- cacheMap
+}
+
+// release forgets pending thunks once they are resolved, unless they were
+// already replaced by a later Load after a Clear.
+func (l *loader[K, V, C]) release(cacheKeys []C, thunks []*Thunk[V]) {
+	cacheMap := <-l.cacheMap
+	for index, cacheKey := range cacheKeys {
+		if l.pending[cacheKey] == thunks[index] {
+			delete(l.pending, cacheKey)
+		}
+	}
+	l.cacheMap <- cacheMap
+}
+
+func (l *loader[K, V, C]) cacheHit(ctx context.Context, key K) {
+	l.count(func(stats *Stats) { stats.CacheHits++ })
+	if hook, ok := l.hook.(CacheHook[K]); ok {
+		hook.OnCacheHit(ctx, key)
+	}
+}
+
+func (l *loader[K, V, C]) cacheMiss(ctx context.Context, key K) {
+	l.count(func(stats *Stats) { stats.CacheMisses++ })
+	if hook, ok := l.hook.(CacheHook[K]); ok {
+		hook.OnCacheMiss(ctx, key)
+	}
+}
+
+// named returns ctx carrying the loader name, if the loader has one.
+func (l *loader[K, V, C]) named(ctx context.Context) context.Context {
+	if l.name == "" {
+		return ctx
+	}
+	return contextWithLoaderName(ctx, l.name)
+}
+
+// wrap prefixes errors produced by the loader with its name, if it has one.
+func (l *loader[K, V, C]) wrap(err error) error {
+	if l.name == "" {
+		return err
+	}
+	return fmt.Errorf("dataloader %s: %w", l.name, err)
+}
+
+func (l *loader[K, V, C]) batchError(ctx context.Context, keys []K, err error) {
+	if hook, ok := l.hook.(BatchErrorHook[K]); ok {
+		hook.OnBatchError(ctx, keys, err)
+	}
+}
+
 func (l *loader[K, V, C]) Clear(ctx context.Context, key K) DataLoader[K, V, C] {
+	ctx = l.named(ctx)
+	if hook, ok := l.hook.(ClearHook[K]); ok {
+		hook.OnClear(ctx, key)
+	}
+
 	cacheKey, _ := l.cacheKeyFn(ctx, key)
 	cacheMap := <-l.cacheMap
 	cacheMap.Delete(ctx, cacheKey)
+	delete(l.pending, cacheKey)
 	l.cacheMap <- cacheMap
+
+	if l.invalidator != nil {
+		l.invalidator.Publish(ctx, Invalidation[C]{Keys: []C{cacheKey}})
+	}
 	return l
 }
 
 func (l *loader[K, V, C]) ClearAll(ctx context.Context) DataLoader[K, V, C] {
+	ctx = l.named(ctx)
+	if hook, ok := l.hook.(ClearHook[K]); ok {
+		hook.OnClearAll(ctx)
+	}
+
 	cacheMap := <-l.cacheMap
 	cacheMap.Clear(ctx)
+	l.pending = make(map[C]*Thunk[V])
+	l.cacheMap <- cacheMap
+
+	if l.invalidator != nil {
+		l.invalidator.Publish(ctx, Invalidation[C]{All: true})
+	}
+	return l
+}
+
+// ClearFunc removes every cached entry whose cache key matches fn. It does
+// nothing unless the cache map is an IterableCacheMap.
+func (l *loader[K, V, C]) ClearFunc(ctx context.Context, fn func(C) bool) DataLoader[K, V, C] {
+	ctx = l.named(ctx)
+	keys := []C{}
+
+	cacheMap := <-l.cacheMap
+	if iterable, ok := cacheMap.(IterableCacheMap[C, *Thunk[V]]); ok {
+		iterable.Range(ctx, func(cacheKey C, _ *Thunk[V]) bool {
+			if fn(cacheKey) {
+				keys = append(keys, cacheKey)
+			}
+			return true
+		})
+		for cacheKey := range l.pending {
+			if fn(cacheKey) {
+				keys = append(keys, cacheKey)
+			}
+		}
+	}
+	for _, cacheKey := range keys {
+		cacheMap.Delete(ctx, cacheKey)
+		delete(l.pending, cacheKey)
+	}
 	l.cacheMap <- cacheMap
+
+	if hook, ok := l.hook.(ClearMatchingHook); ok {
+		hook.OnClearFunc(ctx, len(keys))
+	}
+
+	if l.invalidator != nil && len(keys) != 0 {
+		l.invalidator.Publish(ctx, Invalidation[C]{Keys: keys})
+	}
 	return l
 }
 
+// invalidate applies an invalidation received from the Invalidator without
+// publishing it again.
+func (l *loader[K, V, C]) invalidate(ctx context.Context, inv Invalidation[C]) {
+	cacheMap := <-l.cacheMap
+	if inv.All {
+		cacheMap.Clear(ctx)
+		l.pending = make(map[C]*Thunk[V])
+	}
+	for _, cacheKey := range inv.Keys {
+		cacheMap.Delete(ctx, cacheKey)
+		delete(l.pending, cacheKey)
+	}
+	if tagged, ok := cacheMap.(TaggedCacheMap[C, *Thunk[V]]); ok {
+		for _, tag := range inv.Tags {
+			tagged.DeleteTag(ctx, tag)
+		}
+	}
+	l.cacheMap <- cacheMap
+}
+
 func (l *loader[K, V, C]) Prime(ctx context.Context, key K, value V) DataLoader[K, V, C] {
+	ctx = l.named(ctx)
+	if hook, ok := l.hook.(PrimeHook[K, V]); ok {
+		hook.OnPrime(ctx, key, value)
+	}
+
 	cacheKey, _ := l.cacheKeyFn(ctx, key)
 	thunk := NewThunk[V]()
 	thunk.set(ctx, value)
@@ -214,3 +746,34 @@ func (l *loader[K, V, C]) Prime(ctx context.Context, key K, value V) DataLoader[
 
 	return l
 }
+
+// PrimeWithTags primes the cache like Prime, and attaches tags to the entry
+// when the cache map is a TaggedCacheMap.
+func (l *loader[K, V, C]) PrimeWithTags(ctx context.Context, key K, value V, tags ...string) DataLoader[K, V, C] {
+	l.Prime(ctx, key, value)
+
+	cacheKey, _ := l.cacheKeyFn(ctx, key)
+	l.tag(ctx, []C{cacheKey}, []Result[V]{{Value: value, Tags: tags}})
+
+	return l
+}
+
+// ClearTag removes every cached entry carrying tag. It does nothing unless the
+// cache map is a TaggedCacheMap.
+func (l *loader[K, V, C]) ClearTag(ctx context.Context, tag string) DataLoader[K, V, C] {
+	ctx = l.named(ctx)
+	if hook, ok := l.hook.(ClearMatchingHook); ok {
+		hook.OnClearTag(ctx, tag)
+	}
+
+	cacheMap := <-l.cacheMap
+	if tagged, ok := cacheMap.(TaggedCacheMap[C, *Thunk[V]]); ok {
+		tagged.DeleteTag(ctx, tag)
+	}
+	l.cacheMap <- cacheMap
+
+	if l.invalidator != nil {
+		l.invalidator.Publish(ctx, Invalidation[C]{Tags: []string{tag}})
+	}
+	return l
+}
@@ -7,6 +7,7 @@ import (
 
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -15,6 +16,8 @@ import (
 type recordHook struct {
 	before [][]string
 	after  [][]string
+	hits   []string
+	misses []string
 }
 
 func (h *recordHook) BeforeBatch(_ context.Context, keys []string) {
@@ -25,6 +28,94 @@ func (h *recordHook) AfterBatch(_ context.Context, keys []string, _ []Result[str
 	h.after = append(h.after, append([]string(nil), keys...))
 }
 
+func (h *recordHook) OnCacheHit(_ context.Context, key string) {
+	h.hits = append(h.hits, key)
+}
+
+func (h *recordHook) OnCacheMiss(_ context.Context, key string) {
+	h.misses = append(h.misses, key)
+}
+
+type MultiGetCache[C comparable, V any] struct {
+	*InMemoryCache[C, V]
+	mu   sync.Mutex
+	gets [][]C
+	sets [][]C
+}
+
+func (c *MultiGetCache[C, V]) getCalls() [][]C {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([][]C(nil), c.gets...)
+}
+
+func (c *MultiGetCache[C, V]) setCalls() [][]C {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([][]C(nil), c.sets...)
+}
+
+func NewMultiGetCache[C comparable, V any]() *MultiGetCache[C, V] {
+	return &MultiGetCache[C, V]{InMemoryCache: NewInMemoryCache[C, V]()}
+}
+
+// MultiGetCache guards the embedded cache with mu, since the loader calls
+// GetMany without holding its own lock.
+func (c *MultiGetCache[C, V]) GetMany(ctx context.Context, keys []C) ([]V, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gets = append(c.gets, append([]C(nil), keys...))
+	vals := make([]V, len(keys))
+	for index, key := range keys {
+		vals[index], _ = c.InMemoryCache.Get(ctx, key)
+	}
+	return vals, nil
+}
+
+func (c *MultiGetCache[C, V]) SetMany(ctx context.Context, keys []C, vals []V) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sets = append(c.sets, append([]C(nil), keys...))
+	for index, key := range keys {
+		c.InMemoryCache.Set(ctx, key, vals[index])
+	}
+	return nil
+}
+
+func (c *MultiGetCache[C, V]) Set(ctx context.Context, key C, val V) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.InMemoryCache.Set(ctx, key, val)
+}
+
+func (c *MultiGetCache[C, V]) Delete(ctx context.Context, key C) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.InMemoryCache.Delete(ctx, key)
+}
+
+func (c *MultiGetCache[C, V]) Clear(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.InMemoryCache.Clear(ctx)
+}
+
+// BlockingGetCache blocks the first GetMany until release is closed.
+type BlockingGetCache[C comparable, V any] struct {
+	*MultiGetCache[C, V]
+	started chan struct{}
+	release chan struct{}
+	once    sync.Once
+}
+
+func (c *BlockingGetCache[C, V]) GetMany(ctx context.Context, keys []C) ([]V, error) {
+	c.once.Do(func() {
+		close(c.started)
+		<-c.release
+	})
+	return c.MultiGetCache.GetMany(ctx, keys)
+}
+
 type ErrorCache[C comparable, V any] struct {
 	err   error
 	errOn string
@@ -557,6 +648,258 @@ var _ = Describe("DataLoader", func() {
 		Expect(hook.before).To(HaveLen(1))
 		Expect(hook.after).To(HaveLen(1))
 	})
+
+	It("pass the batch and the contexts of the load calls to the batch", func() {
+		type ctxKey struct{}
+		ctx := context.TODO()
+		loadCtxs := make(chan []context.Context, 1)
+
+		batchLoadFn := func(ctx context.Context, keys []string) []Result[string] {
+			defer GinkgoRecover()
+			batch, ok := BatchFromContext(ctx)
+			Expect(ok).To(BeTrue())
+			Expect(batch.Len()).To(Equal(len(keys)))
+
+			loadCtxs <- LoadContextsFromContext(ctx)
+			return loadValues(ctx, keys)
+		}
+
+		loader := New[string, string, string](ctx, batchLoadFn,
+			WithBatchScheduleFn[string, string, string](NewTimeWindowScheduler(1*time.Second)),
+		)
+
+		thunk := loader.Load(context.WithValue(ctx, ctxKey{}, "first"), "foo")
+		loader.Load(context.WithValue(ctx, ctxKey{}, "second"), "bar")
+		loader.Dispatch()
+		_, err := thunk.Get(ctx)
+		Expect(err).To(BeNil())
+
+		ctxs := <-loadCtxs
+		Expect(ctxs).To(HaveLen(2))
+		Expect(ctxs[0].Value(ctxKey{})).To(Equal("first"))
+		Expect(ctxs[1].Value(ctxKey{})).To(Equal("second"))
+	})
+
+	It("lookup batch cache once per batch and only load misses", func() {
+		ctx := context.TODO()
+		hook := &recordHook{}
+		cacheMap := NewMultiGetCache[string, *Thunk[string]]()
+		cached := NewThunk[string]()
+		cached.set(ctx, "cached:foo")
+		cacheMap.Set(ctx, "foo", cached)
+
+		loadKeys := [][]string{}
+		batchLoadFn := func(ctx context.Context, keys []string) []Result[string] {
+			defer GinkgoRecover()
+			loadKeys = append(loadKeys, append([]string(nil), keys...))
+			result := make([]Result[string], len(keys))
+			for index, key := range keys {
+				result[index] = Result[string]{Value: "res:" + key}
+			}
+			return result
+		}
+
+		loader := New[string, string, string](ctx, batchLoadFn,
+			WithBatchScheduleFn[string, string, string](NewTimeWindowScheduler(1*time.Second)),
+			WithCacheMap[string, string, string](cacheMap),
+			WithHook[string, string, string](hook),
+		)
+
+		thunks := loader.LoadMany(ctx, []string{"foo", "bar", "baz", "bar"})
+		Expect(thunks[3]).To(BeIdenticalTo(thunks[1]))
+		loader.Dispatch()
+
+		for index, expected := range []string{"cached:foo", "res:bar", "res:baz", "res:bar"} {
+			val, err := thunks[index].Get(ctx)
+			Expect(err).To(BeNil())
+			Expect(val).To(Equal(expected))
+		}
+
+		Expect(cacheMap.getCalls()).To(Equal([][]string{{"foo", "bar", "baz"}}))
+		Expect(loadKeys).To(Equal([][]string{{"bar", "baz"}}))
+		Eventually(func() [][]string { return cacheMap.setCalls() }).Should(Equal([][]string{{"bar", "baz"}}))
+		Expect(hook.hits).To(Equal([]string{"bar", "foo"}))
+		Expect(hook.misses).To(Equal([]string{"bar", "baz"}))
+	})
+
+	It("not hold the loader lock while looking up the batch cache", func() {
+		ctx := context.TODO()
+		cacheMap := &BlockingGetCache[string, *Thunk[string]]{
+			MultiGetCache: NewMultiGetCache[string, *Thunk[string]](),
+			started:       make(chan struct{}),
+			release:       make(chan struct{}),
+		}
+		loader := New[string, string, string](ctx, loadValues, WithCacheMap[string, string, string](cacheMap))
+
+		thunk := loader.Load(ctx, "a")
+		loader.Dispatch()
+		<-cacheMap.started
+
+		cleared := make(chan struct{})
+		go func() {
+			loader.Load(ctx, "b")
+			loader.Clear(ctx, "b")
+			close(cleared)
+		}()
+		Eventually(cleared).Should(BeClosed())
+
+		close(cacheMap.release)
+		_, err := thunk.Get(ctx)
+		Expect(err).To(BeNil())
+	})
+
+	It("not cache keys cleared while batch is in flight", func() {
+		ctx := context.TODO()
+		cacheMap := NewMultiGetCache[string, *Thunk[string]]()
+		started := make(chan struct{})
+		release := make(chan struct{})
+		batchLoadFn := func(ctx context.Context, keys []string) []Result[string] {
+			close(started)
+			<-release
+			result := make([]Result[string], len(keys))
+			for index, key := range keys {
+				result[index] = Result[string]{Value: "res:" + key}
+			}
+			return result
+		}
+
+		loader := New[string, string, string](ctx, batchLoadFn, WithCacheMap[string, string, string](cacheMap))
+		thunk := loader.Load(ctx, "a")
+		loader.Dispatch()
+		<-started
+		loader.Clear(ctx, "a")
+		close(release)
+
+		val, err := thunk.Get(ctx)
+		Expect(err).To(BeNil())
+		Expect(val).To(Equal("res:a"))
+		Consistently(func() int {
+			n, _ := cacheMap.Len(ctx)
+			return n
+		}, 50*time.Millisecond).Should(Equal(0))
+	})
+
+	It("clear tagged entries from results and prime", func() {
+		ctx := context.TODO()
+		loadCount := 0
+		batchLoadFn := func(ctx context.Context, keys []string) []Result[string] {
+			loadCount += 1
+			result := make([]Result[string], len(keys))
+			for index, key := range keys {
+				result[index] = Result[string]{Value: "res:" + key, Tags: []string{"user:42"}}
+			}
+			return result
+		}
+
+		loader := New[string, string, string](ctx, batchLoadFn)
+		loader.PrimeWithTags(ctx, "primed", "bar", "user:42")
+		loader.Prime(ctx, "untagged", "baz")
+		_, err := loader.Load(ctx, "foo").Get(ctx)
+		Expect(err).To(BeNil())
+		Expect(loadCount).To(Equal(1))
+
+		loader.ClearTag(ctx, "user:42")
+
+		thunks := loader.LoadMany(ctx, []string{"foo", "primed", "untagged"})
+		for index, expected := range []string{"res:foo", "res:primed", "baz"} {
+			val, err := thunks[index].Get(ctx)
+			Expect(err).To(BeNil())
+			Expect(val).To(Equal(expected))
+		}
+		Expect(loadCount).To(Equal(2))
+	})
+
+	It("keep untagged primed value when clearing the tag of the old value", func() {
+		ctx := context.TODO()
+		loadCount := 0
+		batchLoadFn := func(ctx context.Context, keys []string) []Result[string] {
+			loadCount += 1
+			return make([]Result[string], len(keys))
+		}
+
+		loader := New[string, string, string](ctx, batchLoadFn)
+		loader.PrimeWithTags(ctx, "foo", "v1", "user:42")
+		loader.Prime(ctx, "foo", "v2")
+		loader.ClearTag(ctx, "user:42")
+
+		val, err := loader.Load(ctx, "foo").Get(ctx)
+		Expect(err).To(BeNil())
+		Expect(val).To(Equal("v2"))
+		Expect(loadCount).To(Equal(0))
+	})
+
+	It("tag entries before resolving thunks", func() {
+		ctx := context.TODO()
+		batchLoadFn := func(ctx context.Context, keys []string) []Result[string] {
+			result := make([]Result[string], len(keys))
+			for index, key := range keys {
+				result[index] = Result[string]{Value: "res:" + key, Tags: []string{"user:42"}}
+			}
+			return result
+		}
+
+		for _, cacheMap := range []CacheMap[string, *Thunk[string]]{
+			NewInMemoryCache[string, *Thunk[string]](),
+			NewMultiGetCache[string, *Thunk[string]](),
+		} {
+			loader := New[string, string, string](ctx, batchLoadFn, WithCacheMap[string, string, string](cacheMap))
+			for i := 0; i < 20; i++ {
+				_, err := loader.Load(ctx, fmt.Sprint(i)).Get(ctx)
+				Expect(err).To(BeNil())
+				loader.ClearTag(ctx, "user:42")
+				Expect(cacheMap.(SizedCacheMap[string, *Thunk[string]]).Len(ctx)).To(Equal(0))
+			}
+		}
+	})
+
+	It("clear entries matching predicate", func() {
+		ctx := context.TODO()
+		loadCount := 0
+		batchLoadFn := func(ctx context.Context, keys []string) []Result[string] {
+			loadCount += 1
+			result := make([]Result[string], len(keys))
+			for index, key := range keys {
+				result[index] = Result[string]{Value: "res:" + key}
+			}
+			return result
+		}
+
+		cacheMap := NewInMemoryCache[string, *Thunk[string]]()
+		loader := New[string, string, string](ctx, batchLoadFn, WithCacheMap[string, string, string](cacheMap))
+		keys := []string{"tenant1:foo", "tenant1:bar", "tenant2:foo"}
+		for _, thunk := range loader.LoadMany(ctx, keys) {
+			_, err := thunk.Get(ctx)
+			Expect(err).To(BeNil())
+		}
+		Expect(loadCount).To(Equal(1))
+
+		loader.ClearFunc(ctx, func(key string) bool { return strings.HasPrefix(key, "tenant1:") })
+		Expect(cacheMap.items).To(HaveLen(1))
+		Expect(cacheMap.items).To(HaveKey("tenant2:foo"))
+
+		_, err := loader.Load(ctx, "tenant1:foo").Get(ctx)
+		Expect(err).To(BeNil())
+		Expect(loadCount).To(Equal(2))
+	})
+
+	It("skip batch load function when every key hits batch cache", func() {
+		ctx := context.TODO()
+		cacheMap := NewMultiGetCache[string, *Thunk[string]]()
+		loadCount := 0
+		batchLoadFn := func(ctx context.Context, keys []string) []Result[string] {
+			loadCount += 1
+			return make([]Result[string], len(keys))
+		}
+
+		loader := New[string, string, string](ctx, batchLoadFn, WithCacheMap[string, string, string](cacheMap))
+		loader.Prime(ctx, "foo", "bar")
+
+		val, err := loader.Load(ctx, "foo").Get(ctx)
+		Expect(err).To(BeNil())
+		Expect(val).To(Equal("bar"))
+		Expect(loadCount).To(Equal(0))
+		Expect(cacheMap.getCalls()).To(HaveLen(1))
+	})
 })
 
 func TestDataloader(t *testing.T) {
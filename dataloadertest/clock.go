@@ -0,0 +1,160 @@
+// Package dataloadertest provides helpers for testing code built on the
+// dataloader package.
+package dataloadertest
+
+import (
+	"sort"
+	"time"
+
+	"github.com/yckao/go-dataloader"
+)
+
+// FakeClock is a dataloader.Clock which only moves when Advance is called.
+// Pass it to a loader with dataloader.WithClock to test time windows without
+// sleeping.
+type FakeClock struct {
+	state chan *fakeClockState
+}
+
+type fakeClockState struct {
+	now     time.Time
+	timers  []*fakeTimer
+	changed chan struct{}
+}
+
+type fakeTimer struct {
+	clock    *FakeClock
+	deadline time.Time
+	c        chan time.Time
+	fn       func()
+}
+
+// NewFakeClock returns a FakeClock starting at now.
+func NewFakeClock(now time.Time) *FakeClock {
+	c := &FakeClock{
+		state: make(chan *fakeClockState, 1),
+	}
+	c.state <- &fakeClockState{now: now, changed: make(chan struct{})}
+	return c
+}
+
+func (c *FakeClock) Now() time.Time {
+	state := <-c.state
+	now := state.now
+	c.state <- state
+	return now
+}
+
+func (c *FakeClock) NewTimer(d time.Duration) dataloader.Timer {
+	t := &fakeTimer{clock: c, c: make(chan time.Time, 1)}
+	c.schedule(t, d)
+	return t
+}
+
+func (c *FakeClock) AfterFunc(d time.Duration, f func()) dataloader.Timer {
+	t := &fakeTimer{clock: c, fn: f}
+	c.schedule(t, d)
+	return t
+}
+
+// Advance moves the clock forward by d and fires every timer due by then, in
+// deadline order. AfterFunc functions run synchronously.
+func (c *FakeClock) Advance(d time.Duration) {
+	state := <-c.state
+	state.now = state.now.Add(d)
+	now := state.now
+
+	due := []*fakeTimer{}
+	pending := []*fakeTimer{}
+	for _, t := range state.timers {
+		if t.deadline.After(now) {
+			pending = append(pending, t)
+		} else {
+			due = append(due, t)
+		}
+	}
+	state.timers = pending
+	c.notify(state)
+	c.state <- state
+
+	sort.SliceStable(due, func(i, j int) bool { return due[i].deadline.Before(due[j].deadline) })
+	for _, t := range due {
+		if t.fn != nil {
+			t.fn()
+			continue
+		}
+		select {
+		case t.c <- now:
+		default:
+		}
+	}
+}
+
+// Timers returns the number of timers waiting to fire.
+func (c *FakeClock) Timers() int {
+	state := <-c.state
+	n := len(state.timers)
+	c.state <- state
+	return n
+}
+
+// BlockUntil waits until n timers are waiting to fire. Schedulers create their
+// timers in their own goroutine, so tests call it before Advance.
+func (c *FakeClock) BlockUntil(n int) {
+	for {
+		state := <-c.state
+		count := len(state.timers)
+		changed := state.changed
+		c.state <- state
+
+		if count >= n {
+			return
+		}
+		<-changed
+	}
+}
+
+func (c *FakeClock) schedule(t *fakeTimer, d time.Duration) {
+	state := <-c.state
+	t.deadline = state.now.Add(d)
+	state.timers = append(state.timers, t)
+	c.notify(state)
+	c.state <- state
+}
+
+// remove unschedules t and reports whether it was waiting to fire.
+func (c *FakeClock) remove(t *fakeTimer) bool {
+	state := <-c.state
+	active := false
+	for index, timer := range state.timers {
+		if timer == t {
+			state.timers = append(state.timers[:index], state.timers[index+1:]...)
+			active = true
+			break
+		}
+	}
+	if active {
+		c.notify(state)
+	}
+	c.state <- state
+	return active
+}
+
+func (c *FakeClock) notify(state *fakeClockState) {
+	close(state.changed)
+	state.changed = make(chan struct{})
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	return t.clock.remove(t)
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	active := t.clock.remove(t)
+	t.clock.schedule(t, d)
+	return active
+}
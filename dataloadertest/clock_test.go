@@ -0,0 +1,135 @@
+package dataloadertest
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yckao/go-dataloader"
+)
+
+var _ = Describe("FakeClock", func() {
+	var (
+		ctx         context.Context
+		clock       *FakeClock
+		loaded      chan []string
+		batchLoadFn dataloader.BatchLoadFn[string, string]
+	)
+
+	BeforeEach(func() {
+		ctx = context.TODO()
+		clock = NewFakeClock(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+		loaded = make(chan []string, 10)
+		batchLoadFn = func(ctx context.Context, keys []string) []dataloader.Result[string] {
+			loaded <- append([]string(nil), keys...)
+			return make([]dataloader.Result[string], len(keys))
+		}
+	})
+
+	It("only move when advanced", func() {
+		start := clock.Now()
+		clock.Advance(5 * time.Second)
+		Expect(clock.Now().Sub(start)).To(Equal(5 * time.Second))
+	})
+
+	It("fire timers in deadline order", func() {
+		fired := []int{}
+		clock.AfterFunc(20*time.Millisecond, func() { fired = append(fired, 2) })
+		clock.AfterFunc(10*time.Millisecond, func() { fired = append(fired, 1) })
+		timer := clock.NewTimer(15 * time.Millisecond)
+
+		clock.Advance(12 * time.Millisecond)
+		Expect(fired).To(Equal([]int{1}))
+		Expect(timer.C()).NotTo(Receive())
+
+		clock.Advance(10 * time.Millisecond)
+		Expect(fired).To(Equal([]int{1, 2}))
+		Expect(timer.C()).To(Receive())
+		Expect(clock.Timers()).To(Equal(0))
+	})
+
+	It("stop and reset timers", func() {
+		timer := clock.NewTimer(10 * time.Millisecond)
+		Expect(timer.Stop()).To(BeTrue())
+		Expect(timer.Stop()).To(BeFalse())
+
+		Expect(timer.Reset(10 * time.Millisecond)).To(BeFalse())
+		clock.Advance(10 * time.Millisecond)
+		Expect(timer.C()).To(Receive())
+	})
+
+	It("expire loader time window deterministically", func() {
+		loader := dataloader.New[string, string, string](ctx, batchLoadFn,
+			dataloader.WithClock[string, string, string](clock),
+		)
+
+		thunk := loader.Load(ctx, "foo")
+		clock.BlockUntil(1)
+
+		clock.Advance(15 * time.Millisecond)
+		Consistently(loaded, 20*time.Millisecond).ShouldNot(Receive())
+
+		clock.Advance(1 * time.Millisecond)
+		Eventually(loaded).Should(Receive(Equal([]string{"foo"})))
+		_, err := thunk.Get(ctx)
+		Expect(err).To(BeNil())
+	})
+
+	It("drive idle scheduler deterministically", func() {
+		loader := dataloader.New[string, string, string](ctx, batchLoadFn,
+			dataloader.WithClock[string, string, string](clock),
+			dataloader.WithBatchScheduleFn[string, string, string](dataloader.NewIdleScheduler(10*time.Millisecond, 1*time.Second)),
+		)
+
+		loader.Load(ctx, "foo")
+		clock.BlockUntil(2)
+		clock.Advance(9 * time.Millisecond)
+
+		loader.Load(ctx, "bar")
+		Eventually(func() time.Duration {
+			return nextDeadline(clock)
+		}).Should(Equal(10 * time.Millisecond))
+
+		clock.Advance(9 * time.Millisecond)
+		Consistently(loaded, 20*time.Millisecond).ShouldNot(Receive())
+
+		clock.Advance(1 * time.Millisecond)
+		Eventually(loaded).Should(Receive(Equal([]string{"foo", "bar"})))
+	})
+
+	It("record batch creation time", func() {
+		observed := make(chan time.Time, 1)
+		loader := dataloader.New[string, string, string](ctx, batchLoadFn,
+			dataloader.WithClock[string, string, string](clock),
+			dataloader.WithBatchScheduleFn[string, string, string](func(ctx context.Context, batch dataloader.Batch, callback func()) {
+				observed <- batch.CreatedAt()
+				callback()
+			}),
+		)
+
+		loader.Load(ctx, "foo")
+		Eventually(observed).Should(Receive(Equal(clock.Now())))
+	})
+})
+
+// nextDeadline returns how long until the earliest timer of clock fires.
+func nextDeadline(clock *FakeClock) time.Duration {
+	state := <-clock.state
+	defer func() { clock.state <- state }()
+
+	next := time.Duration(-1)
+	for _, t := range state.timers {
+		if d := t.deadline.Sub(state.now); next < 0 || d < next {
+			next = d
+		}
+	}
+	return next
+}
+
+func TestDataloadertest(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Dataloadertest Suite")
+}
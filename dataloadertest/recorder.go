@@ -0,0 +1,169 @@
+package dataloadertest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+
+	"github.com/yckao/go-dataloader"
+)
+
+// RecordedBatch is one call of a batch load function.
+type RecordedBatch[K any, V any] struct {
+	Keys    []K
+	Results []dataloader.Result[V]
+}
+
+// Recorder stores the keys and results of every batch of a loader. Either pass
+// it to dataloader.WithHook, or wrap the batch load function with Wrap, but
+// not both.
+type Recorder[K any, V any] struct {
+	batches chan []RecordedBatch[K, V]
+}
+
+func NewRecorder[K any, V any]() *Recorder[K, V] {
+	r := &Recorder[K, V]{
+		batches: make(chan []RecordedBatch[K, V], 1),
+	}
+	r.batches <- []RecordedBatch[K, V]{}
+	return r
+}
+
+// Wrap returns a batch load function calling fn and recording its batches.
+func (r *Recorder[K, V]) Wrap(fn dataloader.BatchLoadFn[K, V]) dataloader.BatchLoadFn[K, V] {
+	return func(ctx context.Context, keys []K) []dataloader.Result[V] {
+		results := fn(ctx, keys)
+		r.record(keys, results)
+		return results
+	}
+}
+
+func (r *Recorder[K, V]) BeforeBatch(ctx context.Context, keys []K) {}
+
+func (r *Recorder[K, V]) AfterBatch(ctx context.Context, keys []K, results []dataloader.Result[V]) {
+	r.record(keys, results)
+}
+
+func (r *Recorder[K, V]) record(keys []K, results []dataloader.Result[V]) {
+	batch := RecordedBatch[K, V]{
+		Keys:    append([]K(nil), keys...),
+		Results: append([]dataloader.Result[V](nil), results...),
+	}
+
+	batches := <-r.batches
+	r.batches <- append(batches, batch)
+}
+
+// Batches returns the recorded batches in the order they completed.
+func (r *Recorder[K, V]) Batches() []RecordedBatch[K, V] {
+	batches := <-r.batches
+	r.batches <- batches
+	return append([]RecordedBatch[K, V](nil), batches...)
+}
+
+// Keys returns the keys of every recorded batch.
+func (r *Recorder[K, V]) Keys() [][]K {
+	batches := r.Batches()
+	keys := make([][]K, len(batches))
+	for index, batch := range batches {
+		keys[index] = batch.Keys
+	}
+	return keys
+}
+
+// Reset forgets the recorded batches.
+func (r *Recorder[K, V]) Reset() {
+	<-r.batches
+	r.batches <- []RecordedBatch[K, V]{}
+}
+
+func (r *Recorder[K, V]) batchKeys() [][]interface{} {
+	batches := r.Batches()
+	keys := make([][]interface{}, len(batches))
+	for index, batch := range batches {
+		keys[index] = make([]interface{}, len(batch.Keys))
+		for i, key := range batch.Keys {
+			keys[index][i] = key
+		}
+	}
+	return keys
+}
+
+type batchKeysRecorder interface {
+	batchKeys() [][]interface{}
+}
+
+// HaveBatchCount succeeds when a Recorder recorded exactly count batches.
+func HaveBatchCount(count int) types.GomegaMatcher {
+	return &recorderMatcher{
+		description: fmt.Sprintf("to have recorded %d batches", count),
+		match: func(batches [][]interface{}) (bool, error) {
+			return len(batches) == count, nil
+		},
+	}
+}
+
+// HaveBatchWithKeys succeeds when a Recorder recorded a batch made of exactly
+// keys, in any order.
+func HaveBatchWithKeys(keys ...interface{}) types.GomegaMatcher {
+	return &recorderMatcher{
+		description: fmt.Sprintf("to have recorded a batch with keys %v", keys),
+		match: func(batches [][]interface{}) (bool, error) {
+			for _, batch := range batches {
+				ok, err := gomega.ConsistOf(keys...).Match(batch)
+				if err != nil {
+					return false, err
+				}
+				if ok {
+					return true, nil
+				}
+			}
+			return false, nil
+		},
+	}
+}
+
+// HaveBatchSizes succeeds when the batches of a Recorder have exactly sizes,
+// in order.
+func HaveBatchSizes(sizes ...int) types.GomegaMatcher {
+	return &recorderMatcher{
+		description: fmt.Sprintf("to have recorded batches of sizes %v", sizes),
+		match: func(batches [][]interface{}) (bool, error) {
+			if len(batches) != len(sizes) {
+				return false, nil
+			}
+			for index, batch := range batches {
+				if len(batch) != sizes[index] {
+					return false, nil
+				}
+			}
+			return true, nil
+		},
+	}
+}
+
+type recorderMatcher struct {
+	description string
+	match       func(batches [][]interface{}) (bool, error)
+	batches     [][]interface{}
+}
+
+func (m *recorderMatcher) Match(actual interface{}) (bool, error) {
+	recorder, ok := actual.(batchKeysRecorder)
+	if !ok {
+		return false, fmt.Errorf("expected a *dataloadertest.Recorder, got\n%s", format.Object(actual, 1))
+	}
+	m.batches = recorder.batchKeys()
+	return m.match(m.batches)
+}
+
+func (m *recorderMatcher) FailureMessage(actual interface{}) string {
+	return format.Message(m.batches, m.description)
+}
+
+func (m *recorderMatcher) NegatedFailureMessage(actual interface{}) string {
+	return format.Message(m.batches, "not "+m.description)
+}
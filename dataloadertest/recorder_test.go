@@ -0,0 +1,78 @@
+package dataloadertest
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"context"
+	"time"
+
+	"github.com/yckao/go-dataloader"
+)
+
+func loadValues(ctx context.Context, keys []string) []dataloader.Result[string] {
+	results := make([]dataloader.Result[string], len(keys))
+	for index, key := range keys {
+		results[index] = dataloader.Result[string]{Value: "res:" + key}
+	}
+	return results
+}
+
+func loadBatches(loader dataloader.DataLoader[string, string, string], batches ...[]string) {
+	ctx := context.TODO()
+	for _, keys := range batches {
+		thunks := loader.LoadMany(ctx, keys)
+		loader.Dispatch()
+		for _, thunk := range thunks {
+			_, err := thunk.Get(ctx)
+			Expect(err).To(BeNil())
+		}
+	}
+}
+
+var _ = Describe("Recorder", func() {
+	It("record batches as a hook", func() {
+		recorder := NewRecorder[string, string]()
+		loader := dataloader.New[string, string, string](context.TODO(), loadValues,
+			dataloader.WithBatchScheduleFn[string, string, string](dataloader.NewTimeWindowScheduler(1*time.Second)),
+			dataloader.WithHook[string, string, string](recorder),
+		)
+
+		loadBatches(loader, []string{"a", "b"}, []string{"c"})
+
+		Expect(recorder.Keys()).To(Equal([][]string{{"a", "b"}, {"c"}}))
+		Expect(recorder.Batches()[1].Results).To(Equal([]dataloader.Result[string]{{Value: "res:c"}}))
+		Expect(recorder).To(HaveBatchCount(2))
+		Expect(recorder).To(HaveBatchWithKeys("b", "a"))
+		Expect(recorder).ToNot(HaveBatchWithKeys("a"))
+		Expect(recorder).To(HaveBatchSizes(2, 1))
+
+		recorder.Reset()
+		Expect(recorder).To(HaveBatchCount(0))
+	})
+
+	It("record batches of a wrapped batch load function", func() {
+		recorder := NewRecorder[string, string]()
+		loader := dataloader.New[string, string, string](context.TODO(), recorder.Wrap(loadValues),
+			dataloader.WithBatchScheduleFn[string, string, string](dataloader.NewTimeWindowScheduler(1*time.Second)),
+		)
+
+		loadBatches(loader, []string{"a"}, []string{"b"}, []string{"a", "c"})
+
+		Expect(recorder).To(HaveBatchSizes(1, 1, 1))
+		Expect(recorder).To(HaveBatchWithKeys("c"))
+	})
+
+	It("explain failures", func() {
+		recorder := NewRecorder[string, string]()
+		matcher := HaveBatchCount(1)
+
+		ok, err := matcher.Match(recorder)
+		Expect(err).To(BeNil())
+		Expect(ok).To(BeFalse())
+		Expect(matcher.FailureMessage(recorder)).To(ContainSubstring("to have recorded 1 batches"))
+
+		_, err = matcher.Match("not a recorder")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,163 @@
+// Package debug serves the live state of loaders over HTTP, to diagnose stuck
+// thunks and batching efficiency in production.
+package debug
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yckao/go-dataloader"
+)
+
+// Handler is an http.Handler listing the registered loaders. It serves JSON
+// when the request has format=json or accepts application/json, and an HTML
+// page otherwise.
+type Handler struct {
+	loaders chan map[string]*registration
+}
+
+type registration struct {
+	loader  dataloader.StatsProvider
+	history HistorySource
+}
+
+// LoaderState is the state of one registered loader.
+type LoaderState struct {
+	Name    string           `json:"name"`
+	Stats   dataloader.Stats `json:"stats"`
+	Pending []PendingBatch   `json:"pending"`
+	History []BatchRecord    `json:"history"`
+}
+
+// PendingBatch is a batch waiting to be dispatched.
+type PendingBatch struct {
+	CreatedAt time.Time     `json:"created_at"`
+	Age       time.Duration `json:"age"`
+	Priority  string        `json:"priority"`
+	Partition string        `json:"partition,omitempty"`
+	Size      int           `json:"size"`
+	Keys      []string      `json:"keys"`
+}
+
+func NewHandler() *Handler {
+	h := &Handler{
+		loaders: make(chan map[string]*registration, 1),
+	}
+	h.loaders <- map[string]*registration{}
+	return h
+}
+
+// Register lists loader under name, replacing a loader registered with the
+// same name. Pending keys are listed when the loader is a
+// dataloader.BatchInspector, which every loader is. The history is optional
+// and can be nil. Calling the returned function removes the loader.
+func (h *Handler) Register(name string, loader dataloader.StatsProvider, history HistorySource) (unregister func()) {
+	reg := &registration{loader: loader, history: history}
+
+	loaders := <-h.loaders
+	loaders[name] = reg
+	h.loaders <- loaders
+
+	return func() {
+		loaders := <-h.loaders
+		if current, ok := loaders[name]; ok && current == reg {
+			delete(loaders, name)
+		}
+		h.loaders <- loaders
+	}
+}
+
+// State returns the state of every registered loader, sorted by name.
+func (h *Handler) State() []LoaderState {
+	loaders := <-h.loaders
+	names := make([]string, 0, len(loaders))
+	regs := make(map[string]*registration, len(loaders))
+	for name, reg := range loaders {
+		names = append(names, name)
+		regs[name] = reg
+	}
+	h.loaders <- loaders
+	sort.Strings(names)
+
+	now := time.Now()
+	states := make([]LoaderState, len(names))
+	for index, name := range names {
+		reg := regs[name]
+		state := LoaderState{
+			Name:    name,
+			Stats:   reg.loader.Stats(),
+			Pending: []PendingBatch{},
+			History: []BatchRecord{},
+		}
+		if inspector, ok := reg.loader.(dataloader.BatchInspector); ok {
+			for _, batch := range inspector.PendingBatches() {
+				state.Pending = append(state.Pending, PendingBatch{
+					CreatedAt: batch.CreatedAt,
+					Age:       now.Sub(batch.CreatedAt),
+					Priority:  batch.Priority.String(),
+					Partition: batch.Partition,
+					Size:      len(batch.Keys),
+					Keys:      formatKeys(batch.Keys),
+				})
+			}
+		}
+		if reg.history != nil {
+			state.History = reg.history.Batches()
+		}
+		states[index] = state
+	}
+	return states
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	states := h.State()
+
+	if r.URL.Query().Get("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json") {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(states)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := page.Execute(w, states); err != nil {
+		http.Error(w, fmt.Sprint(err), http.StatusInternalServerError)
+	}
+}
+
+var page = template.Must(template.New("debug").Parse(`<!DOCTYPE html>
+<html>
+<head><title>dataloader</title></head>
+<body>
+<h1>dataloader</h1>
+{{range .}}
+<h2>{{.Name}}</h2>
+<table>
+<tr><th>loads</th><td>{{.Stats.Loads}}</td></tr>
+<tr><th>cache hits</th><td>{{.Stats.CacheHits}}</td></tr>
+<tr><th>batches</th><td>{{.Stats.Batches}}</td></tr>
+<tr><th>mean batch size</th><td>{{printf "%.2f" .Stats.MeanBatchSize}}</td></tr>
+<tr><th>pending batches</th><td>{{.Stats.PendingBatches}}</td></tr>
+<tr><th>in flight batches</th><td>{{.Stats.InFlightBatches}}</td></tr>
+<tr><th>cache size</th><td>{{.Stats.CacheSize}}</td></tr>
+</table>
+<h3>pending</h3>
+<table>
+<tr><th>age</th><th>priority</th><th>partition</th><th>size</th><th>keys</th></tr>
+{{range .Pending}}<tr><td>{{.Age}}</td><td>{{.Priority}}</td><td>{{.Partition}}</td><td>{{.Size}}</td><td>{{range .Keys}}{{.}} {{end}}</td></tr>
+{{end}}</table>
+<h3>history</h3>
+<table>
+<tr><th>start</th><th>duration</th><th>size</th><th>errors</th><th>keys</th></tr>
+{{range .History}}<tr><td>{{.Start.Format "15:04:05.000"}}</td><td>{{.Duration}}</td><td>{{.Size}}</td><td>{{.Errors}}</td><td>{{range .Keys}}{{.}} {{end}}</td></tr>
+{{end}}</table>
+{{else}}
+<p>no loader registered</p>
+{{end}}
+</body>
+</html>
+`))
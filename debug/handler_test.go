@@ -0,0 +1,170 @@
+package debug
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/yckao/go-dataloader"
+	"github.com/yckao/go-dataloader/dataloadertest"
+)
+
+func TestDebug(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Debug Suite")
+}
+
+func batchLoadFn(ctx context.Context, keys []string) []dataloader.Result[string] {
+	results := make([]dataloader.Result[string], len(keys))
+	for index, key := range keys {
+		if key == "bad" {
+			results[index] = dataloader.Result[string]{Error: errors.New("bad key")}
+		} else {
+			results[index] = dataloader.Result[string]{Value: key}
+		}
+	}
+	return results
+}
+
+func get(server *httptest.Server, path string, accept string) (*http.Response, string) {
+	req, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+	Expect(err).To(BeNil())
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	res, err := server.Client().Do(req)
+	Expect(err).To(BeNil())
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	Expect(err).To(BeNil())
+	return res, string(body)
+}
+
+var _ = Describe("Handler", func() {
+	var (
+		handler *Handler
+		server  *httptest.Server
+		loader  dataloader.DataLoader[string, string, string]
+		history *History[string, string]
+	)
+
+	BeforeEach(func() {
+		ctx := context.TODO()
+		handler = NewHandler()
+		server = httptest.NewServer(handler)
+		history = NewHistory[string, string](2)
+		loader = dataloader.New[string, string, string](ctx, batchLoadFn,
+			dataloader.WithBatchScheduleFn[string, string, string](dataloader.NewTimeWindowScheduler(1*time.Minute)),
+			dataloader.WithHook[string, string, string](history),
+		)
+
+		for _, keys := range [][]string{{"a"}, {"b", "bad"}, {"c"}} {
+			thunks := loader.LoadMany(ctx, keys)
+			loader.Dispatch()
+			for _, thunk := range thunks {
+				thunk.Get(ctx)
+			}
+		}
+		loader.Load(ctx, "stuck")
+	})
+
+	AfterEach(func() {
+		loader.Dispatch()
+		server.Close()
+	})
+
+	It("serve loader state as json", func() {
+		unregister := handler.Register("users", loader.(dataloader.StatsProvider), history)
+		defer unregister()
+
+		res, body := get(server, "/?format=json", "")
+		Expect(res.Header.Get("Content-Type")).To(Equal("application/json"))
+
+		states := []LoaderState{}
+		Expect(json.Unmarshal([]byte(body), &states)).To(Succeed())
+		Expect(states).To(HaveLen(1))
+
+		state := states[0]
+		Expect(state.Name).To(Equal("users"))
+		Expect(state.Stats.Batches).To(Equal(uint64(3)))
+		Expect(state.Stats.PendingBatches).To(Equal(1))
+		Expect(state.Pending).To(HaveLen(1))
+		Expect(state.Pending[0].Keys).To(Equal([]string{"stuck"}))
+		Expect(state.Pending[0].Priority).To(Equal("normal"))
+
+		Expect(state.History).To(HaveLen(2))
+		Expect(state.History[0].Keys).To(Equal([]string{"c"}))
+		Expect(state.History[1].Keys).To(Equal([]string{"b", "bad"}))
+		Expect(state.History[1].Errors).To(Equal(1))
+	})
+
+	It("serve loader state as html", func() {
+		unregister := handler.Register("users", loader.(dataloader.StatsProvider), nil)
+		defer unregister()
+
+		res, body := get(server, "/", "text/html")
+		Expect(res.Header.Get("Content-Type")).To(HavePrefix("text/html"))
+		Expect(body).To(ContainSubstring("<h2>users</h2>"))
+		Expect(body).To(ContainSubstring("stuck"))
+
+		_, body = get(server, "/", "application/json")
+		Expect(body).To(HavePrefix("["))
+	})
+
+	It("unregister loaders", func() {
+		unregister := handler.Register("users", loader.(dataloader.StatsProvider), history)
+		unregister()
+
+		Expect(handler.State()).To(BeEmpty())
+		_, body := get(server, "/", "")
+		Expect(body).To(ContainSubstring("no loader registered"))
+	})
+})
+
+var _ = Describe("History", func() {
+	It("record batches with the loader clock", func() {
+		ctx := context.TODO()
+		clock := dataloadertest.NewFakeClock(time.Unix(0, 0))
+		slowBatchLoadFn := func(ctx context.Context, keys []string) []dataloader.Result[string] {
+			clock.Advance(10 * time.Millisecond)
+			return batchLoadFn(ctx, keys)
+		}
+
+		history := NewHistory[string, string](1)
+		loader := dataloader.New[string, string, string](ctx, slowBatchLoadFn,
+			dataloader.WithClock[string, string, string](clock),
+			dataloader.WithBatchScheduleFn[string, string, string](dataloader.NewTimeWindowScheduler(1*time.Minute)),
+			dataloader.WithHook[string, string, string](history),
+		)
+
+		thunk := loader.Load(ctx, "a")
+		loader.Dispatch()
+		_, err := thunk.Get(ctx)
+		Expect(err).To(BeNil())
+
+		batches := history.Batches()
+		Expect(batches).To(HaveLen(1))
+		Expect(batches[0].Start).To(Equal(time.Unix(0, 0)))
+		Expect(batches[0].Duration).To(Equal(10 * time.Millisecond))
+	})
+
+	It("keep no batches with a negative size", func() {
+		ctx := context.TODO()
+		history := NewHistory[string, string](-1)
+		loader := dataloader.New[string, string, string](ctx, batchLoadFn,
+			dataloader.WithHook[string, string, string](history),
+		)
+
+		_, err := loader.Load(ctx, "a").Get(ctx)
+		Expect(err).To(BeNil())
+		Expect(history.Batches()).To(BeEmpty())
+	})
+})
@@ -0,0 +1,100 @@
+package debug
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yckao/go-dataloader"
+)
+
+// maxRecordKeys caps the keys kept per batch record.
+const maxRecordKeys = 20
+
+// BatchRecord describes an executed batch.
+type BatchRecord struct {
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"`
+	Size     int           `json:"size"`
+	Errors   int           `json:"errors"`
+	Keys     []string      `json:"keys"`
+}
+
+// HistorySource lists recently executed batches, newest first.
+type HistorySource interface {
+	Batches() []BatchRecord
+}
+
+// History is a dataloader.Hook keeping the last executed batches of a loader
+// in a ring buffer.
+type History[K any, V any] struct {
+	records chan *historyRing
+}
+
+type historyRing struct {
+	entries []BatchRecord
+	next    int
+	full    bool
+}
+
+// NewHistory returns a hook keeping the last size batches. A size below one
+// keeps none.
+func NewHistory[K any, V any](size int) *History[K, V] {
+	if size < 0 {
+		size = 0
+	}
+	h := &History[K, V]{
+		records: make(chan *historyRing, 1),
+	}
+	h.records <- &historyRing{entries: make([]BatchRecord, size)}
+	return h
+}
+
+func (h *History[K, V]) BeforeBatch(ctx context.Context, keys []K) {}
+
+func (h *History[K, V]) AfterBatch(ctx context.Context, keys []K, results []dataloader.Result[V]) {
+	record := BatchRecord{Size: len(keys), Keys: formatKeys(keys)}
+	if start, ok := dataloader.BatchStartFromContext(ctx); ok {
+		record.Start = start
+		record.Duration = dataloader.ClockFromContext(ctx).Now().Sub(record.Start)
+	}
+	for _, res := range results {
+		if res.Error != nil {
+			record.Errors++
+		}
+	}
+
+	ring := <-h.records
+	if len(ring.entries) != 0 {
+		ring.entries[ring.next] = record
+		ring.next = (ring.next + 1) % len(ring.entries)
+		ring.full = ring.full || ring.next == 0
+	}
+	h.records <- ring
+}
+
+func (h *History[K, V]) Batches() []BatchRecord {
+	ring := <-h.records
+	n := ring.next
+	if ring.full {
+		n = len(ring.entries)
+	}
+	records := make([]BatchRecord, 0, n)
+	for i := 1; i <= n; i++ {
+		records = append(records, ring.entries[(ring.next-i+len(ring.entries))%len(ring.entries)])
+	}
+	h.records <- ring
+	return records
+}
+
+func formatKeys[K any](keys []K) []string {
+	n := len(keys)
+	if n > maxRecordKeys {
+		n = maxRecordKeys
+	}
+	formatted := make([]string, n)
+	for index := range formatted {
+		formatted[index] = fmt.Sprint(keys[index])
+	}
+	return formatted
+}
@@ -0,0 +1,110 @@
+package dataloader
+
+import (
+	"context"
+	"time"
+)
+
+// DispatchGroup shares one schedule window between many loaders, typically
+// every loader of one GraphQL request, so nested resolvers do not add up a
+// window per loader. Loaders join it with WithDispatchGroup.
+type DispatchGroup struct {
+	window time.Duration
+	state  chan *dispatchGroupState
+}
+
+type dispatchGroupState struct {
+	members []*dispatchGroupMember
+	armed   bool
+}
+
+// dispatchGroupMember is a registered loader with the context it was created
+// with, so the group can drop it once the context is done.
+type dispatchGroupMember struct {
+	ctx    context.Context
+	loader interface{ Dispatch() }
+}
+
+// NewDispatchGroup returns a group which dispatches every registered loader
+// window after the first batch of any of them was created.
+func NewDispatchGroup(window time.Duration) *DispatchGroup {
+	g := &DispatchGroup{
+		window: window,
+		state:  make(chan *dispatchGroupState, 1),
+	}
+	g.state <- &dispatchGroupState{}
+	return g
+}
+
+// DispatchAll dispatches the pending batches of every registered loader.
+// Loaders whose context is done are removed from the group instead.
+func (g *DispatchGroup) DispatchAll() {
+	state := <-g.state
+	loaders := make([]interface{ Dispatch() }, 0, len(state.members))
+	members := state.members[:0]
+	for _, member := range state.members {
+		if member.ctx.Err() == nil {
+			members = append(members, member)
+			loaders = append(loaders, member.loader)
+		}
+	}
+	for index := len(members); index < len(state.members); index++ {
+		state.members[index] = nil
+	}
+	state.members = members
+	g.state <- state
+
+	for _, loader := range loaders {
+		loader.Dispatch()
+	}
+}
+
+// add registers loader until ctx is done or the returned function is called.
+func (g *DispatchGroup) add(ctx context.Context, loader interface{ Dispatch() }) (remove func()) {
+	member := &dispatchGroupMember{ctx: ctx, loader: loader}
+
+	state := <-g.state
+	state.members = append(state.members, member)
+	g.state <- state
+
+	return func() {
+		state := <-g.state
+		for index, registered := range state.members {
+			if registered == member {
+				state.members = append(state.members[:index], state.members[index+1:]...)
+				break
+			}
+		}
+		g.state <- state
+	}
+}
+
+// arm starts the group window unless it is already running.
+func (g *DispatchGroup) arm(clock Clock) {
+	state := <-g.state
+	if !state.armed {
+		state.armed = true
+		clock.AfterFunc(g.window, g.fire)
+	}
+	g.state <- state
+}
+
+func (g *DispatchGroup) fire() {
+	state := <-g.state
+	state.armed = false
+	g.state <- state
+
+	g.DispatchAll()
+}
+
+func (g *DispatchGroup) schedule(ctx context.Context, batch Batch, callback func()) {
+	g.arm(ClockFromContext(ctx))
+	select {
+	case <-ctx.Done():
+		return
+	case <-batch.Dispatch():
+		callback()
+	case <-batch.Full():
+		callback()
+	}
+}
@@ -0,0 +1,113 @@
+package dataloader
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"context"
+	"io"
+	"time"
+)
+
+var _ = Describe("DispatchGroup", func() {
+	var (
+		loaded      chan time.Time
+		batchLoadFn BatchLoadFn[string, string]
+	)
+
+	BeforeEach(func() {
+		loaded = make(chan time.Time, 10)
+		batchLoadFn = func(ctx context.Context, keys []string) []Result[string] {
+			loaded <- time.Now()
+			result := make([]Result[string], len(keys))
+			for index, key := range keys {
+				result[index] = Result[string]{Value: "res:" + key}
+			}
+			return result
+		}
+	})
+
+	It("dispatch every loader in one shared window", func() {
+		ctx := context.TODO()
+		group := NewDispatchGroup(100 * time.Millisecond)
+		l1 := New[string, string, string](ctx, batchLoadFn, WithDispatchGroup[string, string, string](group))
+		l2 := New[string, string, string](ctx, batchLoadFn, WithDispatchGroup[string, string, string](group))
+
+		start := time.Now()
+		t1 := l1.Load(ctx, "foo")
+		time.Sleep(50 * time.Millisecond)
+		t2 := l2.Load(ctx, "bar")
+
+		for _, thunk := range []*Thunk[string]{t1, t2} {
+			_, err := thunk.Get(ctx)
+			Expect(err).To(BeNil())
+		}
+
+		var first, second time.Time
+		Expect(loaded).To(Receive(&first))
+		Expect(loaded).To(Receive(&second))
+		Expect(second.Sub(start)).To(BeNumerically("<", 140*time.Millisecond))
+	})
+
+	It("can dispatch all loaders manually", func() {
+		ctx := context.TODO()
+		group := NewDispatchGroup(1 * time.Second)
+		l1 := New[string, string, string](ctx, batchLoadFn, WithDispatchGroup[string, string, string](group))
+		l2 := New[string, string, string](ctx, batchLoadFn, WithDispatchGroup[string, string, string](group))
+
+		start := time.Now()
+		t1 := l1.Load(ctx, "foo")
+		t2 := l2.Load(ctx, "bar")
+		group.DispatchAll()
+
+		for _, thunk := range []*Thunk[string]{t1, t2} {
+			_, err := thunk.Get(ctx)
+			Expect(err).To(BeNil())
+		}
+		Expect(time.Since(start)).To(BeNumerically("<", 500*time.Millisecond))
+		Expect(loaded).To(HaveLen(2))
+	})
+
+	It("dispatch full batches without waiting", func() {
+		ctx := context.TODO()
+		group := NewDispatchGroup(1 * time.Second)
+		loader := New[string, string, string](ctx, batchLoadFn,
+			WithMaxBatchSize[string, string, string](1),
+			WithDispatchGroup[string, string, string](group),
+		)
+
+		start := time.Now()
+		_, err := loader.Load(ctx, "foo").Get(ctx)
+		Expect(err).To(BeNil())
+		Expect(time.Since(start)).To(BeNumerically("<", 500*time.Millisecond))
+	})
+
+	It("remove closed loaders", func() {
+		ctx := context.TODO()
+		group := NewDispatchGroup(1 * time.Second)
+		l1 := New[string, string, string](ctx, batchLoadFn, WithDispatchGroup[string, string, string](group))
+		New[string, string, string](ctx, batchLoadFn, WithDispatchGroup[string, string, string](group))
+		Expect(memberCount(group)).To(Equal(2))
+
+		Expect(l1.(io.Closer).Close()).To(Succeed())
+		Expect(memberCount(group)).To(Equal(1))
+	})
+
+	It("remove loaders whose context is done on dispatch", func() {
+		ctx, cancel := context.WithCancel(context.TODO())
+		group := NewDispatchGroup(1 * time.Second)
+		New[string, string, string](ctx, batchLoadFn, WithDispatchGroup[string, string, string](group))
+		New[string, string, string](context.TODO(), batchLoadFn, WithDispatchGroup[string, string, string](group))
+
+		cancel()
+		Expect(memberCount(group)).To(Equal(2))
+		group.DispatchAll()
+		Expect(memberCount(group)).To(Equal(1))
+	})
+})
+
+func memberCount(group *DispatchGroup) int {
+	state := <-group.state
+	defer func() { group.state <- state }()
+	return len(state.members)
+}
@@ -10,7 +10,7 @@ import (
 
 func NewTimeWindowScheduler(t time.Duration) BatchScheduleFn {
 	return func(ctx context.Context, batch Batch, callback func()) {
-		timer := time.NewTimer(t)
+		timer := ClockFromContext(ctx).NewTimer(t)
 		defer timer.Stop()
 		select {
 		case <-ctx.Done():
@@ -19,7 +19,7 @@ func NewTimeWindowScheduler(t time.Duration) BatchScheduleFn {
 			callback()
 		case <-batch.Full():
 			callback()
-		case <-timer.C:
+		case <-timer.C():
 			callback()
 		}
 	}
@@ -16,6 +16,21 @@ func (*MockBatch) Full() <-chan struct{} {
 func (*MockBatch) Dispatch() <-chan struct{} {
 	return make(<-chan struct{})
 }
+func (*MockBatch) Changed() <-chan struct{} {
+	return make(<-chan struct{})
+}
+func (*MockBatch) Waited() <-chan struct{} {
+	return make(<-chan struct{})
+}
+func (*MockBatch) Len() int {
+	return 0
+}
+func (*MockBatch) MaxSize() int {
+	return 0
+}
+func (*MockBatch) CreatedAt() time.Time {
+	return time.Time{}
+}
 
 var _ = Describe("NewTimeWindowScheduler", func() {
 	It("should run after specified duration", func() {
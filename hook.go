@@ -1,6 +1,9 @@
 package dataloader
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Hook is used to observe batch execution.
 type Hook[K any, V any] interface {
@@ -10,3 +13,125 @@ type Hook[K any, V any] interface {
 	// keys and the results produced by the batch load function.
 	AfterBatch(ctx context.Context, keys []K, results []Result[V])
 }
+
+// CacheHook is an optional extension of Hook used to observe cache lookups.
+// The loader checks whether its Hook also implements CacheHook.
+type CacheHook[K any] interface {
+	// OnCacheHit will be called when a key is served from the cache.
+	OnCacheHit(ctx context.Context, key K)
+	// OnCacheMiss will be called when a key has to be loaded by a batch.
+	OnCacheMiss(ctx context.Context, key K)
+}
+
+// LoadHook is an optional extension of Hook called on every Load, before the
+// cache is checked.
+type LoadHook[K any] interface {
+	OnLoad(ctx context.Context, key K)
+}
+
+// PrimeHook is an optional extension of Hook called when the cache is primed.
+type PrimeHook[K any, V any] interface {
+	OnPrime(ctx context.Context, key K, value V)
+}
+
+// ClearHook is an optional extension of Hook called when cache entries are
+// cleared through the loader.
+type ClearHook[K any] interface {
+	OnClear(ctx context.Context, key K)
+	OnClearAll(ctx context.Context)
+}
+
+// ClearMatchingHook is an optional extension of Hook called when cache entries
+// are cleared through the loader by ClearTag or ClearFunc. OnClearFunc gets
+// the number of entries matched by the predicate.
+type ClearMatchingHook interface {
+	OnClearTag(ctx context.Context, tag string)
+	OnClearFunc(ctx context.Context, cleared int)
+}
+
+// DispatchReason tells why a batch was dispatched.
+type DispatchReason int
+
+const (
+	// DispatchScheduled is a batch dispatched by its schedule function or
+	// BatchScheduler, after a time window, an idle interval, a waiting Get or
+	// a Scope tick.
+	DispatchScheduled DispatchReason = iota
+	// DispatchFull is a batch which reached the max batch size.
+	DispatchFull
+	// DispatchManual is a batch dispatched with Dispatch, including the
+	// dispatches of a DispatchGroup.
+	DispatchManual
+)
+
+func (r DispatchReason) String() string {
+	switch r {
+	case DispatchScheduled:
+		return "scheduled"
+	case DispatchFull:
+		return "full"
+	case DispatchManual:
+		return "manual"
+	}
+	return "unknown"
+}
+
+// BatchHook is an optional extension of Hook following the life of batches.
+type BatchHook interface {
+	// OnBatchCreated will be called when a batch is created, before it is
+	// handed to the scheduler.
+	OnBatchCreated(ctx context.Context, batch Batch)
+	// OnBatchDispatched will be called when a batch is dispatched, before its
+	// cache lookup and BeforeBatch.
+	OnBatchDispatched(ctx context.Context, batch Batch, reason DispatchReason)
+}
+
+// BatchErrorHook is an optional extension of Hook called when a whole batch
+// fails, because its cache lookup failed or the batch load function returned
+// a wrong number of results. Errors of single results are not reported.
+type BatchErrorHook[K any] interface {
+	OnBatchError(ctx context.Context, keys []K, err error)
+}
+
+type (
+	loadContextsKey struct{}
+	batchKey        struct{}
+	batchStartKey   struct{}
+	loaderNameKey   struct{}
+)
+
+// LoadContextsFromContext returns the contexts of the Load calls which added
+// keys to the batch, in the order the keys were added. Hooks and the batch load
+// function receive them in their context, for example to link a batch span to
+// the spans of its callers.
+func LoadContextsFromContext(ctx context.Context) []context.Context {
+	ctxs, _ := ctx.Value(loadContextsKey{}).([]context.Context)
+	return ctxs
+}
+
+// BatchFromContext returns the batch being executed, for example to tell how
+// long it waited since CreatedAt. Hooks and the batch load function receive it
+// in their context.
+func BatchFromContext(ctx context.Context) (Batch, bool) {
+	batch, ok := ctx.Value(batchKey{}).(Batch)
+	return batch, ok
+}
+
+// BatchStartFromContext returns when the loader called BeforeBatch, read from
+// the loader's clock. Hooks measure the batch duration in AfterBatch with it
+// instead of keeping their own state per batch.
+func BatchStartFromContext(ctx context.Context) (time.Time, bool) {
+	start, ok := ctx.Value(batchStartKey{}).(time.Time)
+	return start, ok
+}
+
+func contextWithLoaderName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, loaderNameKey{}, name)
+}
+
+// LoaderNameFromContext returns the name of the loader set with WithName.
+// Hooks, schedulers and the batch load function receive it in their context.
+func LoaderNameFromContext(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(loaderNameKey{}).(string)
+	return name, ok
+}
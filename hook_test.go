@@ -0,0 +1,222 @@
+package dataloader
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type eventHook struct {
+	mu     sync.Mutex
+	events []string
+	errs   []error
+}
+
+func (h *eventHook) record(format string, args ...interface{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events = append(h.events, fmt.Sprintf(format, args...))
+}
+
+func (h *eventHook) get() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.events...)
+}
+
+func (h *eventHook) BeforeBatch(_ context.Context, keys []string) {
+	h.record("before %v", keys)
+}
+
+func (h *eventHook) AfterBatch(_ context.Context, keys []string, _ []Result[string]) {
+	h.record("after %v", keys)
+}
+
+func (h *eventHook) OnLoad(_ context.Context, key string) {
+	h.record("load %s", key)
+}
+
+func (h *eventHook) OnCacheHit(_ context.Context, key string) {
+	h.record("hit %s", key)
+}
+
+func (h *eventHook) OnCacheMiss(_ context.Context, key string) {
+	h.record("miss %s", key)
+}
+
+func (h *eventHook) OnPrime(_ context.Context, key string, value string) {
+	h.record("prime %s=%s", key, value)
+}
+
+func (h *eventHook) OnClear(_ context.Context, key string) {
+	h.record("clear %s", key)
+}
+
+func (h *eventHook) OnClearAll(_ context.Context) {
+	h.record("clear all")
+}
+
+func (h *eventHook) OnClearTag(ctx context.Context, tag string) {
+	name, _ := LoaderNameFromContext(ctx)
+	h.record("clear tag %s of %s", tag, name)
+}
+
+func (h *eventHook) OnClearFunc(ctx context.Context, cleared int) {
+	name, _ := LoaderNameFromContext(ctx)
+	h.record("clear func %d of %s", cleared, name)
+}
+
+func (h *eventHook) OnBatchCreated(_ context.Context, batch Batch) {
+	h.record("created")
+}
+
+func (h *eventHook) OnBatchDispatched(_ context.Context, batch Batch, reason DispatchReason) {
+	h.record("dispatched %d %s", batch.Len(), reason)
+}
+
+func (h *eventHook) OnBatchError(_ context.Context, keys []string, err error) {
+	h.record("error %v", keys)
+	h.mu.Lock()
+	h.errs = append(h.errs, err)
+	h.mu.Unlock()
+}
+
+type funcHook struct {
+	after func(ctx context.Context)
+}
+
+func (h *funcHook) BeforeBatch(context.Context, []string) {}
+
+func (h *funcHook) AfterBatch(ctx context.Context, _ []string, _ []Result[string]) {
+	h.after(ctx)
+}
+
+var _ = Describe("Hook events", func() {
+	It("report loads, cache, prime, clear and batch events", func() {
+		ctx := context.TODO()
+		hook := &eventHook{}
+		loader := New[string, string, string](ctx, loadValues,
+			WithBatchScheduleFn[string, string, string](NewTimeWindowScheduler(1*time.Second)),
+			WithHook[string, string, string](hook),
+		)
+
+		thunk := loader.Load(ctx, "foo")
+		loader.Load(ctx, "foo")
+		loader.Dispatch()
+		_, err := thunk.Get(ctx)
+		Expect(err).To(BeNil())
+
+		loader.Prime(ctx, "bar", "baz")
+		loader.Clear(ctx, "bar")
+		loader.ClearAll(ctx)
+
+		Expect(hook.get()).To(Equal([]string{
+			"load foo",
+			"miss foo",
+			"created",
+			"load foo",
+			"hit foo",
+			"dispatched 1 manual",
+			"before [foo]",
+			"after [foo]",
+			"prime bar=baz",
+			"clear bar",
+			"clear all",
+		}))
+	})
+
+	It("report tag and predicate clears with the loader name", func() {
+		ctx := context.TODO()
+		first, second := &eventHook{}, &eventHook{}
+		loader := New[string, string, string](ctx, loadValues,
+			WithName[string, string, string]("users"),
+			WithHook[string, string, string](first),
+			WithHook[string, string, string](second),
+		)
+
+		loader.PrimeWithTags(ctx, "foo", "bar", "user:42")
+		loader.Prime(ctx, "baz", "qux")
+		loader.ClearTag(ctx, "user:42")
+		loader.ClearFunc(ctx, func(key string) bool { return key == "baz" })
+
+		Expect(first.get()).To(Equal(second.get()))
+		Expect(first.get()).To(ContainElements("clear tag user:42 of users", "clear func 1 of users"))
+	})
+
+	It("pass the batch start to hooks", func() {
+		ctx := context.TODO()
+		durations := make(chan time.Duration, 1)
+		hook := &funcHook{after: func(ctx context.Context) {
+			start, ok := BatchStartFromContext(ctx)
+			Expect(ok).To(BeTrue())
+			durations <- time.Since(start)
+		}}
+		batchLoadFn := func(ctx context.Context, keys []string) []Result[string] {
+			time.Sleep(20 * time.Millisecond)
+			return loadValues(ctx, keys)
+		}
+		loader := New[string, string, string](ctx, batchLoadFn, WithHook[string, string, string](hook))
+
+		_, err := loader.Load(ctx, "a").Get(ctx)
+		Expect(err).To(BeNil())
+		Expect(<-durations).To(BeNumerically(">=", 20*time.Millisecond))
+	})
+
+	It("report why batches are dispatched", func() {
+		ctx := context.TODO()
+		hook := &eventHook{}
+		loader := New[string, string, string](ctx, loadValues,
+			WithMaxBatchSize[string, string, string](2),
+			WithBatchScheduleFn[string, string, string](NewTimeWindowScheduler(10*time.Millisecond)),
+			WithHook[string, string, string](hook),
+		)
+
+		for _, thunk := range loader.LoadMany(ctx, []string{"a", "b", "c"}) {
+			_, err := thunk.Get(ctx)
+			Expect(err).To(BeNil())
+		}
+
+		Expect(hook.get()).To(ContainElements("dispatched 2 full", "dispatched 1 scheduled"))
+	})
+
+	It("report dispatch group batches as manually dispatched", func() {
+		ctx := context.TODO()
+		hook := &eventHook{}
+		loader := New[string, string, string](ctx, loadValues,
+			WithDispatchGroup[string, string, string](NewDispatchGroup(1*time.Millisecond)),
+			WithHook[string, string, string](hook),
+		)
+
+		_, err := loader.Load(ctx, "a").Get(ctx)
+		Expect(err).To(BeNil())
+		Expect(hook.get()).To(ContainElement("dispatched 1 manual"))
+	})
+
+	It("report a batch error when results do not match keys", func() {
+		ctx := context.TODO()
+		hook := &eventHook{}
+		batchLoadFn := func(ctx context.Context, keys []string) []Result[string] {
+			return []Result[string]{{Value: "only"}}
+		}
+		loader := New[string, string, string](ctx, batchLoadFn,
+			WithBatchScheduleFn[string, string, string](NewTimeWindowScheduler(1*time.Second)),
+			WithHook[string, string, string](hook),
+		)
+
+		thunks := loader.LoadMany(ctx, []string{"foo", "bar"})
+		loader.Dispatch()
+		for _, thunk := range thunks {
+			_, err := thunk.Get(ctx)
+			Expect(errors.Is(err, ErrBatchResultLength)).To(BeTrue())
+		}
+
+		Expect(hook.get()).To(ContainElement("error [foo bar]"))
+		Expect(hook.errs).To(HaveLen(1))
+		Expect(errors.Is(hook.errs[0], ErrBatchResultLength)).To(BeTrue())
+	})
+})
@@ -0,0 +1,146 @@
+package dataloader
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// Invalidation describes cache entries to drop on every subscriber.
+type Invalidation[C comparable] struct {
+	// Keys are the cache keys to delete.
+	Keys []C
+	// Tags are the tags whose entries are deleted, like ClearTag. They only
+	// apply to caches implementing TaggedCacheMap.
+	Tags []string
+	// All clears every entry, like ClearAll.
+	All bool
+	// Source identifies the bus which published the invalidation. It is set
+	// by the bus and used to ignore its own messages coming back from a
+	// transport.
+	Source string
+}
+
+// Invalidator broadcasts invalidations to every subscribed loader and cache.
+type Invalidator[C comparable] interface {
+	// Publish delivers inv to every subscriber.
+	Publish(ctx context.Context, inv Invalidation[C]) error
+	// Subscribe registers fn to be called for every published invalidation.
+	// Calling the returned function removes the subscription.
+	Subscribe(fn func(ctx context.Context, inv Invalidation[C])) (unsubscribe func())
+}
+
+// InvalidationTransport carries invalidations between processes, for example
+// over a message queue, so that buses in different processes stay in sync.
+type InvalidationTransport[C comparable] interface {
+	// Send forwards inv to the other processes.
+	Send(ctx context.Context, inv Invalidation[C]) error
+	// Receive calls fn for every invalidation sent by other processes until
+	// the returned function is called.
+	Receive(fn func(ctx context.Context, inv Invalidation[C])) (stop func())
+}
+
+// InvalidationBus is an in-process Invalidator. When created with a transport
+// it also forwards invalidations to, and receives them from, other processes.
+type InvalidationBus[C comparable] struct {
+	id          string
+	transport   InvalidationTransport[C]
+	stop        func()
+	subscribers chan *invalidationSubscribers[C]
+}
+
+type invalidationSubscribers[C comparable] struct {
+	next uint64
+	fns  map[uint64]func(ctx context.Context, inv Invalidation[C])
+}
+
+// NewInvalidationBus returns a bus delivering invalidations in process. The
+// transport is optional and can be nil.
+func NewInvalidationBus[C comparable](transport InvalidationTransport[C]) *InvalidationBus[C] {
+	id := make([]byte, 8)
+	rand.Read(id)
+
+	b := &InvalidationBus[C]{
+		id:          hex.EncodeToString(id),
+		transport:   transport,
+		subscribers: make(chan *invalidationSubscribers[C], 1),
+	}
+	b.subscribers <- &invalidationSubscribers[C]{
+		fns: make(map[uint64]func(ctx context.Context, inv Invalidation[C])),
+	}
+
+	if transport != nil {
+		b.stop = transport.Receive(func(ctx context.Context, inv Invalidation[C]) {
+			if inv.Source != b.id {
+				b.deliver(ctx, inv)
+			}
+		})
+	}
+
+	return b
+}
+
+func (b *InvalidationBus[C]) Publish(ctx context.Context, inv Invalidation[C]) error {
+	inv.Source = b.id
+	b.deliver(ctx, inv)
+
+	if b.transport != nil {
+		return b.transport.Send(ctx, inv)
+	}
+	return nil
+}
+
+func (b *InvalidationBus[C]) Subscribe(fn func(ctx context.Context, inv Invalidation[C])) func() {
+	subscribers := <-b.subscribers
+	id := subscribers.next
+	subscribers.next++
+	subscribers.fns[id] = fn
+	b.subscribers <- subscribers
+
+	return func() {
+		subscribers := <-b.subscribers
+		delete(subscribers.fns, id)
+		b.subscribers <- subscribers
+	}
+}
+
+// Close stops receiving invalidations from the transport.
+func (b *InvalidationBus[C]) Close() {
+	if b.stop != nil {
+		b.stop()
+	}
+}
+
+// deliver calls every subscriber outside of the lock, so subscribers are free
+// to publish or unsubscribe.
+func (b *InvalidationBus[C]) deliver(ctx context.Context, inv Invalidation[C]) {
+	subscribers := <-b.subscribers
+	fns := make([]func(ctx context.Context, inv Invalidation[C]), 0, len(subscribers.fns))
+	for _, fn := range subscribers.fns {
+		fns = append(fns, fn)
+	}
+	b.subscribers <- subscribers
+
+	for _, fn := range fns {
+		fn(ctx, inv)
+	}
+}
+
+// SubscribeCacheMap applies every invalidation published on inv to cacheMap.
+// The cache map must be safe for concurrent use if it is shared.
+func SubscribeCacheMap[C comparable, V any](inv Invalidator[C], cacheMap CacheMap[C, V]) (unsubscribe func()) {
+	return inv.Subscribe(func(ctx context.Context, inv Invalidation[C]) {
+		if inv.All {
+			cacheMap.Clear(ctx)
+			return
+		}
+		for _, key := range inv.Keys {
+			cacheMap.Delete(ctx, key)
+		}
+		if tagged, ok := cacheMap.(TaggedCacheMap[C, V]); ok {
+			for _, tag := range inv.Tags {
+				tagged.DeleteTag(ctx, tag)
+			}
+		}
+	})
+}
@@ -0,0 +1,164 @@
+package dataloader
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"context"
+	"io"
+)
+
+type pipeTransport[C comparable] struct {
+	peers *[]func(ctx context.Context, inv Invalidation[C])
+}
+
+func (t *pipeTransport[C]) Send(ctx context.Context, inv Invalidation[C]) error {
+	for _, fn := range *t.peers {
+		fn(ctx, inv)
+	}
+	return nil
+}
+
+func (t *pipeTransport[C]) Receive(fn func(ctx context.Context, inv Invalidation[C])) func() {
+	*t.peers = append(*t.peers, fn)
+	index := len(*t.peers) - 1
+	return func() { (*t.peers)[index] = func(context.Context, Invalidation[C]) {} }
+}
+
+var _ = Describe("InvalidationBus", func() {
+	It("fan out to every subscribed cache map", func() {
+		ctx := context.TODO()
+		bus := NewInvalidationBus[string](nil)
+		c1 := NewInMemoryCache[string, string]()
+		c2 := NewInMemoryCache[string, string]()
+		SubscribeCacheMap[string, string](bus, c1)
+		SubscribeCacheMap[string, string](bus, c2)
+
+		for _, cache := range []*InMemoryCache[string, string]{c1, c2} {
+			cache.Set(ctx, "foo", "1")
+			cache.Set(ctx, "bar", "2")
+		}
+
+		Expect(bus.Publish(ctx, Invalidation[string]{Keys: []string{"foo"}})).To(Succeed())
+		for _, cache := range []*InMemoryCache[string, string]{c1, c2} {
+			Expect(cache.items).To(Equal(map[string]string{"bar": "2"}))
+		}
+
+		Expect(bus.Publish(ctx, Invalidation[string]{All: true})).To(Succeed())
+		for _, cache := range []*InMemoryCache[string, string]{c1, c2} {
+			Expect(cache.items).To(BeEmpty())
+		}
+	})
+
+	It("fan out tag invalidation to tagged cache maps", func() {
+		ctx := context.TODO()
+		bus := NewInvalidationBus[string](nil)
+		cache := NewInMemoryCache[string, string]()
+		SubscribeCacheMap[string, string](bus, cache)
+		cache.Set(ctx, "foo", "1")
+		cache.Set(ctx, "bar", "2")
+		cache.SetTags(ctx, "foo", []string{"user:42"})
+
+		Expect(bus.Publish(ctx, Invalidation[string]{Tags: []string{"user:42"}})).To(Succeed())
+		Expect(cache.items).To(Equal(map[string]string{"bar": "2"}))
+	})
+
+	It("stop delivering after unsubscribe", func() {
+		ctx := context.TODO()
+		bus := NewInvalidationBus[string](nil)
+		received := 0
+		unsubscribe := bus.Subscribe(func(context.Context, Invalidation[string]) { received += 1 })
+
+		bus.Publish(ctx, Invalidation[string]{All: true})
+		unsubscribe()
+		bus.Publish(ctx, Invalidation[string]{All: true})
+
+		Expect(received).To(Equal(1))
+	})
+
+	It("forward through transport without echo", func() {
+		ctx := context.TODO()
+		peers := []func(ctx context.Context, inv Invalidation[string]){}
+		b1 := NewInvalidationBus[string](&pipeTransport[string]{peers: &peers})
+		b2 := NewInvalidationBus[string](&pipeTransport[string]{peers: &peers})
+		defer b1.Close()
+		defer b2.Close()
+
+		r1 := []Invalidation[string]{}
+		r2 := []Invalidation[string]{}
+		b1.Subscribe(func(_ context.Context, inv Invalidation[string]) { r1 = append(r1, inv) })
+		b2.Subscribe(func(_ context.Context, inv Invalidation[string]) { r2 = append(r2, inv) })
+
+		b1.Publish(ctx, Invalidation[string]{Keys: []string{"foo"}})
+
+		Expect(r1).To(HaveLen(1))
+		Expect(r2).To(HaveLen(1))
+		Expect(r2[0].Keys).To(Equal([]string{"foo"}))
+	})
+
+	It("clear every subscribed loader", func() {
+		ctx, cancel := context.WithCancel(context.TODO())
+		defer cancel()
+
+		bus := NewInvalidationBus[string](nil)
+		loadCount := 0
+		batchLoadFn := func(ctx context.Context, keys []string) []Result[string] {
+			loadCount += 1
+			result := make([]Result[string], len(keys))
+			for index, key := range keys {
+				result[index] = Result[string]{Value: "res:" + key}
+			}
+			return result
+		}
+
+		l1 := New[string, string, string](ctx, batchLoadFn, WithInvalidator[string, string, string](bus))
+		l2 := New[string, string, string](ctx, batchLoadFn, WithInvalidator[string, string, string](bus))
+
+		for _, loader := range []DataLoader[string, string, string]{l1, l2} {
+			_, err := loader.Load(ctx, "foo").Get(ctx)
+			Expect(err).To(BeNil())
+		}
+		Expect(loadCount).To(Equal(2))
+
+		l1.Clear(ctx, "foo")
+
+		for _, loader := range []DataLoader[string, string, string]{l1, l2} {
+			_, err := loader.Load(ctx, "foo").Get(ctx)
+			Expect(err).To(BeNil())
+		}
+		Expect(loadCount).To(Equal(4))
+
+		l2.ClearAll(ctx)
+		_, err := l1.Load(ctx, "foo").Get(ctx)
+		Expect(err).To(BeNil())
+		Expect(loadCount).To(Equal(5))
+	})
+
+	It("unsubscribe a loader when it is closed", func() {
+		ctx := context.TODO()
+		bus := NewInvalidationBus[string](nil)
+		loader := New[string, string, string](ctx, loadValues, WithInvalidator[string, string, string](bus))
+		Expect(subscriberCount(bus)).To(Equal(1))
+
+		Expect(loader.(io.Closer).Close()).To(Succeed())
+		Expect(subscriberCount(bus)).To(Equal(0))
+		Expect(loader.(io.Closer).Close()).To(Succeed())
+	})
+
+	It("unsubscribe a loader whose context is done at the next invalidation", func() {
+		ctx, cancel := context.WithCancel(context.TODO())
+		bus := NewInvalidationBus[string](nil)
+		New[string, string, string](ctx, loadValues, WithInvalidator[string, string, string](bus))
+
+		cancel()
+		Expect(subscriberCount(bus)).To(Equal(1))
+		Expect(bus.Publish(context.TODO(), Invalidation[string]{All: true})).To(Succeed())
+		Expect(subscriberCount(bus)).To(Equal(0))
+	})
+})
+
+func subscriberCount[C comparable](bus *InvalidationBus[C]) int {
+	subscribers := <-bus.subscribers
+	defer func() { bus.subscribers <- subscribers }()
+	return len(subscribers.fns)
+}
@@ -0,0 +1,120 @@
+// Package metrics collects per loader counters and histograms through a
+// pluggable Registry, such as the Prometheus adapter of the metrics/prometheus
+// module.
+package metrics
+
+import (
+	"context"
+
+	"github.com/yckao/go-dataloader"
+)
+
+// Counter is a monotonic counter partitioned by loader name.
+type Counter interface {
+	Add(loader string, value float64)
+}
+
+// Histogram records a distribution partitioned by loader name.
+type Histogram interface {
+	Observe(loader string, value float64)
+}
+
+// Registry creates the instruments of a Collector. Every instrument is created
+// once per Collector.
+type Registry interface {
+	Counter(name string, help string) Counter
+	Histogram(name string, help string, buckets []float64) Histogram
+}
+
+var (
+	SizeBuckets     = []float64{1, 2, 5, 10, 20, 50, 100, 200, 500, 1000}
+	ErrorBuckets    = []float64{0, 1, 2, 5, 10, 20, 50, 100}
+	DurationBuckets = []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+)
+
+// Collector holds the instruments shared by the hooks of every loader.
+type Collector struct {
+	loads        Counter
+	cacheHits    Counter
+	cacheMisses  Counter
+	batches      Counter
+	batchSize    Histogram
+	batchLatency Histogram
+	batchErrors  Histogram
+	scheduleWait Histogram
+}
+
+func NewCollector(registry Registry) *Collector {
+	return &Collector{
+		loads:        registry.Counter("dataloader_loads_total", "Number of keys loaded."),
+		cacheHits:    registry.Counter("dataloader_cache_hits_total", "Number of keys served from the cache."),
+		cacheMisses:  registry.Counter("dataloader_cache_misses_total", "Number of keys missing from the cache."),
+		batches:      registry.Counter("dataloader_batches_total", "Number of batch load function calls."),
+		batchSize:    registry.Histogram("dataloader_batch_size", "Number of keys per batch.", SizeBuckets),
+		batchLatency: registry.Histogram("dataloader_batch_duration_seconds", "Duration of batch load function calls.", DurationBuckets),
+		batchErrors:  registry.Histogram("dataloader_batch_errors", "Number of failed keys per batch.", ErrorBuckets),
+		scheduleWait: registry.Histogram("dataloader_schedule_wait_seconds", "Time batches waited in the schedule window.", DurationBuckets),
+	}
+}
+
+// Hook is a dataloader.Hook, dataloader.LoadHook and dataloader.CacheHook
+// recording the metrics of one loader.
+type Hook[K any, V any] struct {
+	collector *Collector
+	loader    string
+}
+
+// NewHook returns a hook recording metrics labeled with the loader name. An
+// empty name falls back to the name set with dataloader.WithName.
+func NewHook[K any, V any](collector *Collector, loader string) *Hook[K, V] {
+	return &Hook[K, V]{
+		collector: collector,
+		loader:    loader,
+	}
+}
+
+func (h *Hook[K, V]) OnLoad(ctx context.Context, key K) {
+	h.collector.loads.Add(h.name(ctx), 1)
+}
+
+func (h *Hook[K, V]) OnCacheHit(ctx context.Context, key K) {
+	h.collector.cacheHits.Add(h.name(ctx), 1)
+}
+
+func (h *Hook[K, V]) OnCacheMiss(ctx context.Context, key K) {
+	h.collector.cacheMisses.Add(h.name(ctx), 1)
+}
+
+func (h *Hook[K, V]) BeforeBatch(ctx context.Context, keys []K) {
+	loader := h.name(ctx)
+	now := dataloader.ClockFromContext(ctx).Now()
+	if batch, ok := dataloader.BatchFromContext(ctx); ok {
+		h.collector.scheduleWait.Observe(loader, now.Sub(batch.CreatedAt()).Seconds())
+	}
+	h.collector.batches.Add(loader, 1)
+	h.collector.batchSize.Observe(loader, float64(len(keys)))
+}
+
+func (h *Hook[K, V]) AfterBatch(ctx context.Context, keys []K, results []dataloader.Result[V]) {
+	loader := h.name(ctx)
+	if start, ok := dataloader.BatchStartFromContext(ctx); ok {
+		latency := dataloader.ClockFromContext(ctx).Now().Sub(start)
+		h.collector.batchLatency.Observe(loader, latency.Seconds())
+	}
+
+	errors := 0
+	for _, res := range results {
+		if res.Error != nil {
+			errors++
+		}
+	}
+	h.collector.batchErrors.Observe(loader, float64(errors))
+}
+
+func (h *Hook[K, V]) name(ctx context.Context) string {
+	if h.loader != "" {
+		return h.loader
+	}
+	name, _ := dataloader.LoaderNameFromContext(ctx)
+	return name
+}
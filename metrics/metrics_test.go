@@ -0,0 +1,146 @@
+package metrics
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yckao/go-dataloader"
+	"github.com/yckao/go-dataloader/dataloadertest"
+)
+
+func TestMetrics(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Metrics Suite")
+}
+
+type recordRegistry struct {
+	mu     sync.Mutex
+	values map[string]map[string][]float64
+}
+
+type recordInstrument struct {
+	registry *recordRegistry
+	name     string
+}
+
+func (i *recordInstrument) Add(loader string, value float64) {
+	i.record(loader, value)
+}
+
+func (i *recordInstrument) Observe(loader string, value float64) {
+	i.record(loader, value)
+}
+
+func (i *recordInstrument) record(loader string, value float64) {
+	i.registry.mu.Lock()
+	defer i.registry.mu.Unlock()
+	if i.registry.values[i.name] == nil {
+		i.registry.values[i.name] = map[string][]float64{}
+	}
+	i.registry.values[i.name][loader] = append(i.registry.values[i.name][loader], value)
+}
+
+func (r *recordRegistry) Counter(name string, help string) Counter {
+	return &recordInstrument{registry: r, name: name}
+}
+
+func (r *recordRegistry) Histogram(name string, help string, buckets []float64) Histogram {
+	return &recordInstrument{registry: r, name: name}
+}
+
+func (r *recordRegistry) get(name string, loader string) []float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.values[name][loader]
+}
+
+var _ = Describe("Hook", func() {
+	It("record loads, cache and batch metrics", func() {
+		ctx := context.TODO()
+		registry := &recordRegistry{values: map[string]map[string][]float64{}}
+		collector := NewCollector(registry)
+		clock := dataloadertest.NewFakeClock(time.Unix(0, 0))
+
+		batchLoadFn := func(ctx context.Context, keys []string) []dataloader.Result[string] {
+			clock.Advance(10 * time.Millisecond)
+			results := make([]dataloader.Result[string], len(keys))
+			for index, key := range keys {
+				if key == "bad" {
+					results[index] = dataloader.Result[string]{Error: errors.New("bad key")}
+				} else {
+					results[index] = dataloader.Result[string]{Value: key}
+				}
+			}
+			return results
+		}
+
+		loader := dataloader.New[string, string, string](ctx, batchLoadFn,
+			dataloader.WithClock[string, string, string](clock),
+			dataloader.WithBatchScheduleFn[string, string, string](dataloader.NewTimeWindowScheduler(1*time.Second)),
+			dataloader.WithHook[string, string, string](NewHook[string, string](collector, "users")),
+		)
+
+		thunks := loader.LoadMany(ctx, []string{"foo", "bar", "bad", "foo"})
+		clock.Advance(5 * time.Millisecond)
+		loader.Dispatch()
+		for _, thunk := range thunks {
+			thunk.Get(ctx)
+		}
+
+		Expect(registry.get("dataloader_loads_total", "users")).To(HaveLen(4))
+		Expect(registry.get("dataloader_cache_hits_total", "users")).To(HaveLen(1))
+		Expect(registry.get("dataloader_cache_misses_total", "users")).To(HaveLen(3))
+		Expect(registry.get("dataloader_batches_total", "users")).To(Equal([]float64{1}))
+		Expect(registry.get("dataloader_batch_size", "users")).To(Equal([]float64{3}))
+		Expect(registry.get("dataloader_batch_errors", "users")).To(Equal([]float64{1}))
+		Expect(registry.get("dataloader_schedule_wait_seconds", "users")).To(Equal([]float64{0.005}))
+		Expect(registry.get("dataloader_batch_duration_seconds", "users")).To(Equal([]float64{0.01}))
+	})
+
+	It("fall back to the loader name", func() {
+		ctx := context.TODO()
+		registry := &recordRegistry{values: map[string]map[string][]float64{}}
+		collector := NewCollector(registry)
+
+		batchLoadFn := func(ctx context.Context, keys []string) []dataloader.Result[string] {
+			return make([]dataloader.Result[string], len(keys))
+		}
+		loader := dataloader.New[string, string, string](ctx, batchLoadFn,
+			dataloader.WithName[string, string, string]("posts"),
+			dataloader.WithHook[string, string, string](NewHook[string, string](collector, "")),
+		)
+
+		_, err := loader.Load(ctx, "foo").Get(ctx)
+		Expect(err).To(BeNil())
+		Expect(registry.get("dataloader_loads_total", "posts")).To(HaveLen(1))
+		Expect(registry.get("dataloader_batches_total", "posts")).To(HaveLen(1))
+	})
+
+	It("count loads failing before the cache", func() {
+		ctx := context.TODO()
+		registry := &recordRegistry{values: map[string]map[string][]float64{}}
+		collector := NewCollector(registry)
+
+		batchLoadFn := func(ctx context.Context, keys []string) []dataloader.Result[string] {
+			return make([]dataloader.Result[string], len(keys))
+		}
+		cacheKeyFn := func(ctx context.Context, key string) (string, error) {
+			return "", errors.New("bad key")
+		}
+		loader := dataloader.New[string, string, string](ctx, batchLoadFn,
+			dataloader.WithCacheKeyFn[string, string, string](cacheKeyFn),
+			dataloader.WithHook[string, string, string](NewHook[string, string](collector, "users")),
+		)
+
+		_, err := loader.Load(ctx, "foo").Get(ctx)
+		Expect(err).To(MatchError("bad key"))
+		Expect(registry.get("dataloader_loads_total", "users")).To(HaveLen(1))
+		Expect(registry.get("dataloader_cache_misses_total", "users")).To(BeEmpty())
+	})
+})
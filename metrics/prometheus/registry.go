@@ -0,0 +1,67 @@
+// Package prometheus adapts a Prometheus registerer to a metrics.Registry.
+package prometheus
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/yckao/go-dataloader/metrics"
+)
+
+// LoaderLabel is the label carrying the loader name.
+const LoaderLabel = "loader"
+
+type registry struct {
+	registerer prometheus.Registerer
+}
+
+type counter struct {
+	vec *prometheus.CounterVec
+}
+
+type histogram struct {
+	vec *prometheus.HistogramVec
+}
+
+// NewRegistry returns a metrics.Registry registering its collectors with
+// registerer, or prometheus.DefaultRegisterer if it is nil. Collectors already
+// registered under the same name are reused, and any other registration error
+// panics like prometheus.MustRegister.
+func NewRegistry(registerer prometheus.Registerer) metrics.Registry {
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+	return &registry{registerer: registerer}
+}
+
+func (r *registry) Counter(name string, help string) metrics.Counter {
+	vec := prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: help}, []string{LoaderLabel})
+	return &counter{vec: register(r.registerer, vec)}
+}
+
+func (r *registry) Histogram(name string, help string, buckets []float64) metrics.Histogram {
+	vec := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: help, Buckets: buckets}, []string{LoaderLabel})
+	return &histogram{vec: register(r.registerer, vec)}
+}
+
+func (c *counter) Add(loader string, value float64) {
+	c.vec.WithLabelValues(loader).Add(value)
+}
+
+func (h *histogram) Observe(loader string, value float64) {
+	h.vec.WithLabelValues(loader).Observe(value)
+}
+
+func register[T prometheus.Collector](registerer prometheus.Registerer, collector T) T {
+	if err := registerer.Register(collector); err != nil {
+		already := prometheus.AlreadyRegisteredError{}
+		if errors.As(err, &already) {
+			if existing, ok := already.ExistingCollector.(T); ok {
+				return existing
+			}
+		}
+		panic(err)
+	}
+	return collector
+}
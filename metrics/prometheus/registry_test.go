@@ -0,0 +1,90 @@
+package prometheus
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/yckao/go-dataloader"
+	"github.com/yckao/go-dataloader/metrics"
+)
+
+func TestPrometheus(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Prometheus Suite")
+}
+
+func batchLoadFn(ctx context.Context, keys []string) []dataloader.Result[string] {
+	results := make([]dataloader.Result[string], len(keys))
+	for index, key := range keys {
+		results[index] = dataloader.Result[string]{Value: key}
+	}
+	return results
+}
+
+var _ = Describe("Registry", func() {
+	It("export loader metrics", func() {
+		ctx := context.TODO()
+		registerer := prometheus.NewPedanticRegistry()
+		collector := metrics.NewCollector(NewRegistry(registerer))
+
+		loader := dataloader.New[string, string, string](ctx, batchLoadFn,
+			dataloader.WithBatchScheduleFn[string, string, string](dataloader.NewTimeWindowScheduler(1*time.Second)),
+			dataloader.WithHook[string, string, string](metrics.NewHook[string, string](collector, "users")),
+		)
+
+		thunks := loader.LoadMany(ctx, []string{"foo", "bar", "foo"})
+		loader.Dispatch()
+		for _, thunk := range thunks {
+			_, err := thunk.Get(ctx)
+			Expect(err).To(BeNil())
+		}
+
+		Expect(testutil.GatherAndCompare(registerer, strings.NewReader(`
+# HELP dataloader_loads_total Number of keys loaded.
+# TYPE dataloader_loads_total counter
+dataloader_loads_total{loader="users"} 3
+# HELP dataloader_cache_hits_total Number of keys served from the cache.
+# TYPE dataloader_cache_hits_total counter
+dataloader_cache_hits_total{loader="users"} 1
+# HELP dataloader_cache_misses_total Number of keys missing from the cache.
+# TYPE dataloader_cache_misses_total counter
+dataloader_cache_misses_total{loader="users"} 2
+# HELP dataloader_batches_total Number of batch load function calls.
+# TYPE dataloader_batches_total counter
+dataloader_batches_total{loader="users"} 1
+`),
+			"dataloader_loads_total",
+			"dataloader_cache_hits_total",
+			"dataloader_cache_misses_total",
+			"dataloader_batches_total",
+		)).To(Succeed())
+
+		Expect(testutil.CollectAndCount(registerer, "dataloader_batch_size")).To(Equal(1))
+		Expect(testutil.CollectAndCount(registerer, "dataloader_batch_duration_seconds")).To(Equal(1))
+		Expect(testutil.CollectAndCount(registerer, "dataloader_batch_errors")).To(Equal(1))
+		Expect(testutil.CollectAndCount(registerer, "dataloader_schedule_wait_seconds")).To(Equal(1))
+	})
+
+	It("reuse collectors already registered", func() {
+		registerer := prometheus.NewPedanticRegistry()
+		first := NewRegistry(registerer).Counter("dataloader_loads_total", "Number of keys loaded.")
+		second := NewRegistry(registerer).Counter("dataloader_loads_total", "Number of keys loaded.")
+
+		first.Add("users", 1)
+		second.Add("users", 2)
+
+		Expect(testutil.GatherAndCompare(registerer, strings.NewReader(`
+# HELP dataloader_loads_total Number of keys loaded.
+# TYPE dataloader_loads_total counter
+dataloader_loads_total{loader="users"} 3
+`))).To(Succeed())
+	})
+})
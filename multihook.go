@@ -0,0 +1,95 @@
+package dataloader
+
+import (
+	"context"
+	"log"
+)
+
+// MultiHook returns a hook calling every hook in order. A panicking hook is
+// logged and does not stop the other hooks. Events of the optional hook
+// interfaces are forwarded to the hooks implementing them.
+func MultiHook[K any, V any](hooks ...Hook[K, V]) Hook[K, V] {
+	m := &multiHook[K, V]{}
+	for _, hook := range hooks {
+		if multi, ok := hook.(*multiHook[K, V]); ok {
+			m.hooks = append(m.hooks, multi.hooks...)
+		} else if hook != nil {
+			m.hooks = append(m.hooks, hook)
+		}
+	}
+	return m
+}
+
+type multiHook[K any, V any] struct {
+	hooks []Hook[K, V]
+}
+
+// forEach calls fn with every hook implementing T, recovering from panics.
+func forEach[T any, K any, V any](m *multiHook[K, V], fn func(T)) {
+	for _, hook := range m.hooks {
+		if h, ok := hook.(T); ok {
+			call(h, fn)
+		}
+	}
+}
+
+func call[T any](hook T, fn func(T)) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("dataloader: hook %T panicked: %v", hook, r)
+		}
+	}()
+	fn(hook)
+}
+
+func (m *multiHook[K, V]) BeforeBatch(ctx context.Context, keys []K) {
+	forEach(m, func(h Hook[K, V]) { h.BeforeBatch(ctx, keys) })
+}
+
+func (m *multiHook[K, V]) AfterBatch(ctx context.Context, keys []K, results []Result[V]) {
+	forEach(m, func(h Hook[K, V]) { h.AfterBatch(ctx, keys, results) })
+}
+
+func (m *multiHook[K, V]) OnCacheHit(ctx context.Context, key K) {
+	forEach(m, func(h CacheHook[K]) { h.OnCacheHit(ctx, key) })
+}
+
+func (m *multiHook[K, V]) OnCacheMiss(ctx context.Context, key K) {
+	forEach(m, func(h CacheHook[K]) { h.OnCacheMiss(ctx, key) })
+}
+
+func (m *multiHook[K, V]) OnLoad(ctx context.Context, key K) {
+	forEach(m, func(h LoadHook[K]) { h.OnLoad(ctx, key) })
+}
+
+func (m *multiHook[K, V]) OnPrime(ctx context.Context, key K, value V) {
+	forEach(m, func(h PrimeHook[K, V]) { h.OnPrime(ctx, key, value) })
+}
+
+func (m *multiHook[K, V]) OnClear(ctx context.Context, key K) {
+	forEach(m, func(h ClearHook[K]) { h.OnClear(ctx, key) })
+}
+
+func (m *multiHook[K, V]) OnClearAll(ctx context.Context) {
+	forEach(m, func(h ClearHook[K]) { h.OnClearAll(ctx) })
+}
+
+func (m *multiHook[K, V]) OnClearTag(ctx context.Context, tag string) {
+	forEach(m, func(h ClearMatchingHook) { h.OnClearTag(ctx, tag) })
+}
+
+func (m *multiHook[K, V]) OnClearFunc(ctx context.Context, cleared int) {
+	forEach(m, func(h ClearMatchingHook) { h.OnClearFunc(ctx, cleared) })
+}
+
+func (m *multiHook[K, V]) OnBatchCreated(ctx context.Context, batch Batch) {
+	forEach(m, func(h BatchHook) { h.OnBatchCreated(ctx, batch) })
+}
+
+func (m *multiHook[K, V]) OnBatchDispatched(ctx context.Context, batch Batch, reason DispatchReason) {
+	forEach(m, func(h BatchHook) { h.OnBatchDispatched(ctx, batch, reason) })
+}
+
+func (m *multiHook[K, V]) OnBatchError(ctx context.Context, keys []K, err error) {
+	forEach(m, func(h BatchErrorHook[K]) { h.OnBatchError(ctx, keys, err) })
+}
@@ -0,0 +1,66 @@
+package dataloader
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"context"
+	"time"
+)
+
+type panicHook struct{}
+
+func (panicHook) BeforeBatch(context.Context, []string) {
+	panic("before")
+}
+
+func (panicHook) AfterBatch(context.Context, []string, []Result[string]) {
+	panic("after")
+}
+
+func (panicHook) OnLoad(context.Context, string) {
+	panic("load")
+}
+
+var _ = Describe("MultiHook", func() {
+	It("call every hook in order and forward optional events", func() {
+		ctx := context.TODO()
+		first, second := &eventHook{}, &eventHook{}
+		records := &recordHook{}
+		loader := New[string, string, string](ctx, loadValues,
+			WithBatchScheduleFn[string, string, string](NewTimeWindowScheduler(1*time.Second)),
+			WithHook[string, string, string](first),
+			WithHook[string, string, string](records),
+			WithHook[string, string, string](second),
+		)
+
+		thunk := loader.Load(ctx, "foo")
+		loader.Dispatch()
+		_, err := thunk.Get(ctx)
+		Expect(err).To(BeNil())
+
+		Expect(first.get()).To(Equal(second.get()))
+		Expect(first.get()).To(ContainElements("load foo", "miss foo", "dispatched 1 manual", "before [foo]", "after [foo]"))
+		Expect(records.before).To(Equal([][]string{{"foo"}}))
+		Expect(records.misses).To(Equal([]string{"foo"}))
+	})
+
+	It("isolate panicking hooks", func() {
+		ctx := context.TODO()
+		hook := &eventHook{}
+		loader := New[string, string, string](ctx, loadValues,
+			WithHook[string, string, string](MultiHook[string, string](panicHook{}, hook)),
+		)
+
+		val, err := loader.Load(ctx, "foo").Get(ctx)
+		Expect(err).To(BeNil())
+		Expect(val).To(Equal("res:foo"))
+		Expect(hook.get()).To(ContainElements("load foo", "before [foo]", "after [foo]"))
+	})
+
+	It("flatten nested multi hooks", func() {
+		first, second, third := &eventHook{}, &eventHook{}, &eventHook{}
+		hook := MultiHook[string, string](MultiHook[string, string](first, second), nil, third)
+		Expect(hook.(*multiHook[string, string]).hooks).To(HaveLen(3))
+	})
+})
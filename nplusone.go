@@ -0,0 +1,138 @@
+package dataloader
+
+import (
+	"fmt"
+	"log"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// NPlusOneConfig configures the N+1 detector enabled by WithNPlusOneDetector.
+type NPlusOneConfig struct {
+	// Threshold is the number of consecutive batches of a single key which
+	// are reported. It defaults to 5.
+	Threshold int
+	// Window is the time span in which they must be dispatched. It defaults
+	// to 1 second.
+	Window time.Duration
+	// Report is called for every detection. It defaults to logging the
+	// report with the log package.
+	Report func(NPlusOneReport)
+}
+
+// NPlusOneReport describes a streak of batches of a single key, which usually
+// means Thunk.Get is called in a loop right after each Load.
+type NPlusOneReport struct {
+	// Loader is the name set with WithName, if any.
+	Loader string
+	// Batches is the number of single key batches in the streak.
+	Batches int
+	// Elapsed is the time between the first and the last of them.
+	Elapsed time.Duration
+	// CallSite is the caller of Load which created the last batch.
+	CallSite string
+	// Stack is the stack of that Load call.
+	Stack string
+}
+
+func (r NPlusOneReport) String() string {
+	name := r.Loader
+	if name == "" {
+		name = "loader"
+	}
+	return fmt.Sprintf("dataloader: possible N+1: %s dispatched %d batches of a single key in %v, loaded from %s\n%s", name, r.Batches, r.Elapsed, r.CallSite, r.Stack)
+}
+
+type nPlusOneDetector struct {
+	config NPlusOneConfig
+	state  chan *nPlusOneState
+}
+
+type nPlusOneState struct {
+	count int
+	first time.Time
+}
+
+const nPlusOneStackDepth = 32
+
+func newNPlusOneDetector(config NPlusOneConfig) *nPlusOneDetector {
+	if config.Threshold <= 0 {
+		config.Threshold = 5
+	}
+	if config.Window <= 0 {
+		config.Window = 1 * time.Second
+	}
+	if config.Report == nil {
+		config.Report = func(report NPlusOneReport) {
+			log.Print(report)
+		}
+	}
+
+	d := &nPlusOneDetector{
+		config: config,
+		state:  make(chan *nPlusOneState, 1),
+	}
+	d.state <- &nPlusOneState{}
+	return d
+}
+
+// callers captures the stack of a Load call, skipping skip frames above it.
+func callers(skip int) []uintptr {
+	pcs := make([]uintptr, nPlusOneStackDepth)
+	return pcs[:runtime.Callers(skip+2, pcs)]
+}
+
+// observe records a dispatched batch and reports a streak of single key
+// batches reaching the threshold within the window.
+func (d *nPlusOneDetector) observe(name string, size int, stack []uintptr, now time.Time) {
+	state := <-d.state
+	if size != 1 {
+		state.count = 0
+		d.state <- state
+		return
+	}
+
+	if state.count == 0 || now.Sub(state.first) > d.config.Window {
+		state.count = 0
+		state.first = now
+	}
+	state.count++
+
+	var report *NPlusOneReport
+	if state.count >= d.config.Threshold {
+		report = &NPlusOneReport{
+			Loader:  name,
+			Batches: state.count,
+			Elapsed: now.Sub(state.first),
+		}
+		state.count = 0
+	}
+	d.state <- state
+
+	if report != nil {
+		report.CallSite, report.Stack = formatStack(stack)
+		d.config.Report(*report)
+	}
+}
+
+// formatStack returns the first frame of stack and the whole stack.
+func formatStack(stack []uintptr) (string, string) {
+	callSite := "unknown"
+	builder := strings.Builder{}
+	frames := runtime.CallersFrames(stack)
+	for {
+		frame, more := frames.Next()
+		if frame.Function != "" {
+			location := fmt.Sprintf("%s:%d", frame.File, frame.Line)
+			if builder.Len() == 0 {
+				callSite = fmt.Sprintf("%s (%s)", frame.Function, location)
+			}
+			fmt.Fprintf(&builder, "%s\n\t%s\n", frame.Function, location)
+		}
+		if !more {
+			break
+		}
+	}
+	return callSite, builder.String()
+}
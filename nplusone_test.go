@@ -0,0 +1,64 @@
+package dataloader
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"context"
+	"fmt"
+	"time"
+)
+
+var _ = Describe("NPlusOneDetector", func() {
+	It("report single key batches dispatched in a loop", func() {
+		ctx := context.TODO()
+		reports := make(chan NPlusOneReport, 10)
+		loader := New[string, string, string](ctx, loadValues,
+			WithName[string, string, string]("users"),
+			WithBatchScheduleFn[string, string, string](NewDispatchOnGetScheduler(1*time.Second)),
+			WithNPlusOneDetector[string, string, string](NPlusOneConfig{
+				Threshold: 3,
+				Report:    func(report NPlusOneReport) { reports <- report },
+			}),
+		)
+
+		for i := 0; i < 4; i++ {
+			_, err := loader.Load(ctx, fmt.Sprintf("key%d", i)).Get(ctx)
+			Expect(err).To(BeNil())
+		}
+
+		Expect(reports).To(HaveLen(1))
+		report := <-reports
+		Expect(report.Loader).To(Equal("users"))
+		Expect(report.Batches).To(Equal(3))
+		Expect(report.CallSite).To(ContainSubstring("nplusone_test.go"))
+		Expect(report.Stack).To(ContainSubstring("nplusone_test.go"))
+		Expect(report.String()).To(ContainSubstring("possible N+1: users dispatched 3 batches"))
+	})
+
+	It("not report batched loads", func() {
+		ctx := context.TODO()
+		reports := make(chan NPlusOneReport, 10)
+		loader := New[string, string, string](ctx, loadValues,
+			WithBatchScheduleFn[string, string, string](NewDispatchOnGetScheduler(1*time.Second)),
+			WithNPlusOneDetector[string, string, string](NPlusOneConfig{
+				Threshold: 2,
+				Report:    func(report NPlusOneReport) { reports <- report },
+			}),
+		)
+
+		for i := 0; i < 4; i++ {
+			for _, thunk := range loader.LoadMany(ctx, []string{fmt.Sprintf("a%d", i), fmt.Sprintf("b%d", i)}) {
+				_, err := thunk.Get(ctx)
+				Expect(err).To(BeNil())
+			}
+		}
+
+		Expect(reports).To(BeEmpty())
+	})
+
+	It("be off by default", func() {
+		dl := New[string, string, string](context.TODO(), loadValues)
+		Expect(dl.(*loader[string, string, string]).nPlusOne).To(BeNil())
+	})
+})
@@ -1,5 +1,7 @@
 package dataloader
 
+import "context"
+
 type option[K any, V any, C comparable] func(*loader[K, V, C])
 
 func WithBatch[K any, V any, C comparable](useBatch bool) option[K, V, C] {
@@ -22,6 +24,33 @@ func WithBatchScheduleFn[K any, V any, C comparable](batchScheduleFn BatchSchedu
 	}
 }
 
+// WithPriorityScheduleFn schedules batches of priority with batchScheduleFn.
+// Without a scheduler option high priority batches wait 1ms and low priority
+// ones 64ms. With WithBatchScheduleFn, WithBatchScheduler or WithDispatchGroup,
+// priorities without their own function use that scheduler.
+func WithPriorityScheduleFn[K any, V any, C comparable](priority Priority, batchScheduleFn BatchScheduleFn) option[K, V, C] {
+	return func(l *loader[K, V, C]) {
+		l.priorityScheduleFns[priority] = batchScheduleFn
+	}
+}
+
+// WithPartitionFn keeps separate batches for each partition returned by
+// partitionFn, so a batch load function call never receives keys of two
+// partitions. Every partition fills and schedules its own batches.
+func WithPartitionFn[K any, V any, C comparable](partitionFn func(K) string) option[K, V, C] {
+	return func(l *loader[K, V, C]) {
+		l.partitionFn = partitionFn
+	}
+}
+
+// WithBatchScheduler schedules batches with scheduler instead of running the
+// batch schedule function in a goroutine per batch.
+func WithBatchScheduler[K any, V any, C comparable](batchScheduler BatchScheduler) option[K, V, C] {
+	return func(l *loader[K, V, C]) {
+		l.batchScheduler = batchScheduler
+	}
+}
+
 func WithCacheKeyFn[K any, V any, C comparable](cacheKeyFn CacheKeyFn[K, C]) option[K, V, C] {
 	return func(l *loader[K, V, C]) {
 		l.cacheKeyFn = cacheKeyFn
@@ -35,8 +64,72 @@ func WithCacheMap[K any, V any, C comparable](cacheMap CacheMap[C, *Thunk[V]]) o
 	}
 }
 
+// WithHook adds hook to the loader. Hooks added by several WithHook options
+// are combined with MultiHook and called in order.
 func WithHook[K any, V any, C comparable](hook Hook[K, V]) option[K, V, C] {
 	return func(l *loader[K, V, C]) {
-		l.hook = hook
+		if l.hook == nil {
+			l.hook = hook
+		} else {
+			l.hook = MultiHook(l.hook, hook)
+		}
+	}
+}
+
+// WithInvalidator subscribes the loader to invalidator, and publishes every
+// Clear and ClearAll on it so other loaders drop the same entries. The
+// subscription ends when the loader is closed, or at the first invalidation
+// delivered after the loader's context is done.
+func WithInvalidator[K any, V any, C comparable](invalidator Invalidator[C]) option[K, V, C] {
+	return func(l *loader[K, V, C]) {
+		l.invalidator = invalidator
+		l.onClose(invalidator.Subscribe(func(ctx context.Context, inv Invalidation[C]) {
+			if l.ctx.Err() != nil {
+				l.Close()
+				return
+			}
+			l.invalidate(ctx, inv)
+		}))
+	}
+}
+
+// WithDispatchGroup registers the loader with group. Its batches are then
+// dispatched together with the batches of every other loader of the group,
+// replacing the batch schedule function. The loader leaves the group when it
+// is closed, or at the first dispatch after the loader's context is done.
+func WithDispatchGroup[K any, V any, C comparable](group *DispatchGroup) option[K, V, C] {
+	return func(l *loader[K, V, C]) {
+		l.batchScheduleFn = group.schedule
+		l.onClose(group.add(l.ctx, l))
+	}
+}
+
+// WithClock sets the clock used by the loader. It is also passed to the batch
+// schedule function and the batch load function through the context, see
+// ClockFromContext.
+func WithClock[K any, V any, C comparable](clock Clock) option[K, V, C] {
+	return func(l *loader[K, V, C]) {
+		l.clock = clock
+		l.ctx = ContextWithClock(l.ctx, clock)
+	}
+}
+
+// WithName names the loader. The name prefixes the errors produced by the
+// loader, and is passed to hooks, schedulers and the batch load function
+// through the context, see LoaderNameFromContext.
+func WithName[K any, V any, C comparable](name string) option[K, V, C] {
+	return func(l *loader[K, V, C]) {
+		l.name = name
+		l.ctx = contextWithLoaderName(l.ctx, name)
+	}
+}
+
+// WithNPlusOneDetector reports when the loader dispatches many batches of a
+// single key in quick succession, which usually means Thunk.Get is called in a
+// loop right after each Load. It captures a stack for every batch, so it is
+// meant for development and is off by default.
+func WithNPlusOneDetector[K any, V any, C comparable](config NPlusOneConfig) option[K, V, C] {
+	return func(l *loader[K, V, C]) {
+		l.nPlusOne = newNPlusOneDetector(config)
 	}
 }
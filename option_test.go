@@ -5,6 +5,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	"context"
+	"errors"
 	"reflect"
 )
 
@@ -50,4 +51,45 @@ var _ = Describe("Option", func() {
 		acutal := <-dl.(*loader[string, string, string]).cacheMap
 		Expect(acutal).To(Equal(cacheMap))
 	})
+
+	It("can set invalidator", func() {
+		bus := NewInvalidationBus[string](nil)
+		dl := New[string, string, string](context.TODO(), func(ctx context.Context, keys []string) []Result[string] { return []Result[string]{} }, WithInvalidator[string, string, string](bus))
+		Expect(dl.(*loader[string, string, string]).invalidator).To(Equal(bus))
+	})
+
+	It("can set name", func() {
+		ctx := context.TODO()
+		names := make(chan string, 2)
+		batchLoadFn := func(ctx context.Context, keys []string) []Result[string] {
+			name, _ := LoaderNameFromContext(ctx)
+			names <- name
+			return []Result[string]{}
+		}
+		cacheKeyFn := func(ctx context.Context, key string) (string, error) {
+			if key == "bad" {
+				return "", errors.New("bad key")
+			}
+			return key, nil
+		}
+
+		dl := New[string, string, string](ctx, batchLoadFn,
+			WithName[string, string, string]("users"),
+			WithCacheKeyFn[string, string, string](cacheKeyFn),
+			WithBatchScheduleFn[string, string, string](func(ctx context.Context, _ Batch, callback func()) {
+				name, _ := LoaderNameFromContext(ctx)
+				names <- name
+				callback()
+			}),
+		)
+
+		_, err := dl.Load(ctx, "bad").Get(ctx)
+		Expect(err).To(MatchError("dataloader users: bad key"))
+
+		_, err = dl.Load(ctx, "foo").Get(ctx)
+		Expect(errors.Is(err, ErrBatchResultLength)).To(BeTrue())
+		Expect(err.Error()).To(HavePrefix("dataloader users: "))
+		Eventually(names).Should(Receive(Equal("users")))
+		Eventually(names).Should(Receive(Equal("users")))
+	})
 })
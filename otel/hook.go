@@ -0,0 +1,137 @@
+// Package otel traces dataloader batches with OpenTelemetry.
+package otel
+
+import (
+	"context"
+	"fmt"
+
+	global "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/yckao/go-dataloader"
+)
+
+const (
+	instrumentationName = "github.com/yckao/go-dataloader/otel"
+	spanName            = "dataloader.batch"
+)
+
+// Attribute keys recorded on batch spans.
+const (
+	LoaderNameKey     = attribute.Key("dataloader.name")
+	BatchSizeKey      = attribute.Key("dataloader.batch.size")
+	BatchErrorsKey    = attribute.Key("dataloader.batch.errors")
+	BatchPriorityKey  = attribute.Key("dataloader.batch.priority")
+	BatchPartitionKey = attribute.Key("dataloader.batch.partition")
+)
+
+// Hook is a dataloader.Hook recording a span around every batch load function
+// call. The span is a child of the loader's context and links to the spans of
+// the Load calls whose keys went into the batch.
+type Hook[K any, V any] struct {
+	tracer trace.Tracer
+	name   string
+}
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	name           string
+}
+
+type Option func(*config)
+
+// WithTracerProvider sets the provider of the tracer. It defaults to the
+// global tracer provider.
+func WithTracerProvider(tracerProvider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tracerProvider
+	}
+}
+
+// WithLoaderName sets the loader name recorded on every span. It defaults to
+// the name set with dataloader.WithName.
+func WithLoaderName(name string) Option {
+	return func(c *config) {
+		c.name = name
+	}
+}
+
+func NewHook[K any, V any](options ...Option) *Hook[K, V] {
+	c := &config{}
+	for _, option := range options {
+		option(c)
+	}
+	if c.tracerProvider == nil {
+		c.tracerProvider = global.GetTracerProvider()
+	}
+
+	return &Hook[K, V]{
+		tracer: c.tracerProvider.Tracer(instrumentationName),
+		name:   c.name,
+	}
+}
+
+func (h *Hook[K, V]) BeforeBatch(ctx context.Context, keys []K) {}
+
+// AfterBatch records the span of the batch, from the batch start to now, so
+// no state is kept between BeforeBatch and AfterBatch.
+func (h *Hook[K, V]) AfterBatch(ctx context.Context, keys []K, results []dataloader.Result[V]) {
+	end := dataloader.ClockFromContext(ctx).Now()
+	start, ok := dataloader.BatchStartFromContext(ctx)
+	if !ok {
+		start = end
+	}
+
+	errors := 0
+	for _, res := range results {
+		if res.Error != nil {
+			errors++
+		}
+	}
+
+	attrs := []attribute.KeyValue{
+		BatchSizeKey.Int(len(keys)),
+		BatchPriorityKey.String(dataloader.PriorityFromContext(ctx).String()),
+		BatchErrorsKey.Int(errors),
+	}
+	if name := h.name; name != "" {
+		attrs = append(attrs, LoaderNameKey.String(name))
+	} else if name, ok := dataloader.LoaderNameFromContext(ctx); ok {
+		attrs = append(attrs, LoaderNameKey.String(name))
+	}
+	if partition, ok := dataloader.PartitionFromContext(ctx); ok {
+		attrs = append(attrs, BatchPartitionKey.String(partition))
+	}
+
+	_, span := h.tracer.Start(ctx, spanName,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(attrs...),
+		trace.WithLinks(links(ctx)...),
+		trace.WithTimestamp(start),
+	)
+	if errors != 0 {
+		span.SetStatus(codes.Error, fmt.Sprintf("%d of %d keys failed", errors, len(results)))
+	}
+	span.End(trace.WithTimestamp(end))
+}
+
+// links returns one link per distinct span among the contexts of the Load
+// calls of the batch.
+func links(ctx context.Context) []trace.Link {
+	seen := map[trace.SpanID]struct{}{}
+	links := []trace.Link{}
+	for _, loadCtx := range dataloader.LoadContextsFromContext(ctx) {
+		spanContext := trace.SpanContextFromContext(loadCtx)
+		if !spanContext.IsValid() {
+			continue
+		}
+		if _, ok := seen[spanContext.SpanID()]; ok {
+			continue
+		}
+		seen[spanContext.SpanID()] = struct{}{}
+		links = append(links, trace.Link{SpanContext: spanContext})
+	}
+	return links
+}
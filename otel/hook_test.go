@@ -0,0 +1,166 @@
+package otel
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/yckao/go-dataloader"
+	"github.com/yckao/go-dataloader/dataloadertest"
+)
+
+func TestOtel(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Otel Suite")
+}
+
+func attributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, attr := range span.Attributes {
+		attrs[attr.Key] = attr.Value
+	}
+	return attrs
+}
+
+var _ = Describe("Hook", func() {
+	var (
+		exporter *tracetest.InMemoryExporter
+		provider *sdktrace.TracerProvider
+	)
+
+	BeforeEach(func() {
+		exporter = tracetest.NewInMemoryExporter()
+		provider = sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	})
+
+	AfterEach(func() {
+		provider.Shutdown(context.Background())
+	})
+
+	It("record a span per batch linked to the callers", func() {
+		ctx := context.TODO()
+		tracer := provider.Tracer("test")
+
+		batchLoadFn := func(ctx context.Context, keys []string) []dataloader.Result[string] {
+			results := make([]dataloader.Result[string], len(keys))
+			for index, key := range keys {
+				if key == "bad" {
+					results[index] = dataloader.Result[string]{Error: errors.New("bad key")}
+				} else {
+					results[index] = dataloader.Result[string]{Value: key}
+				}
+			}
+			return results
+		}
+
+		loader := dataloader.New[string, string, string](ctx, batchLoadFn,
+			dataloader.WithBatchScheduleFn[string, string, string](dataloader.NewTimeWindowScheduler(1*time.Second)),
+			dataloader.WithHook[string, string, string](NewHook[string, string](
+				WithTracerProvider(provider),
+				WithLoaderName("users"),
+			)),
+		)
+
+		ctx1, caller1 := tracer.Start(ctx, "caller1")
+		ctx2, caller2 := tracer.Start(ctx, "caller2")
+		thunks := []*dataloader.Thunk[string]{
+			loader.Load(ctx1, "foo"),
+			loader.Load(ctx1, "bar"),
+			loader.Load(ctx2, "bad"),
+		}
+		loader.Dispatch()
+		for _, thunk := range thunks {
+			thunk.Get(ctx)
+		}
+		caller1.End()
+		caller2.End()
+
+		spans := exporter.GetSpans()
+		Expect(spans).To(HaveLen(3))
+		batch := spans[0]
+		Expect(batch.Name).To(Equal("dataloader.batch"))
+
+		attrs := attributes(batch)
+		Expect(attrs[LoaderNameKey].AsString()).To(Equal("users"))
+		Expect(attrs[BatchSizeKey].AsInt64()).To(Equal(int64(3)))
+		Expect(attrs[BatchErrorsKey].AsInt64()).To(Equal(int64(1)))
+		Expect(attrs[BatchPriorityKey].AsString()).To(Equal("normal"))
+		Expect(batch.Status.Code).To(Equal(codes.Error))
+
+		linked := []trace.SpanID{}
+		for _, link := range batch.Links {
+			linked = append(linked, link.SpanContext.SpanID())
+		}
+		Expect(linked).To(ConsistOf(caller1.SpanContext().SpanID(), caller2.SpanContext().SpanID()))
+	})
+
+	It("record a span per batch of a named loader", func() {
+		ctx := context.TODO()
+
+		batchLoadFn := func(ctx context.Context, keys []string) []dataloader.Result[string] {
+			results := make([]dataloader.Result[string], len(keys))
+			for index, key := range keys {
+				results[index] = dataloader.Result[string]{Value: key}
+			}
+			return results
+		}
+
+		loader := dataloader.New[string, string, string](ctx, batchLoadFn,
+			dataloader.WithName[string, string, string]("posts"),
+			dataloader.WithMaxBatchSize[string, string, string](2),
+			dataloader.WithHook[string, string, string](NewHook[string, string](WithTracerProvider(provider))),
+		)
+
+		for _, thunk := range loader.LoadMany(ctx, []string{"a", "b", "c"}) {
+			_, err := thunk.Get(ctx)
+			Expect(err).To(BeNil())
+		}
+
+		spans := exporter.GetSpans()
+		Expect(spans).To(HaveLen(2))
+		sizes := []int64{}
+		for _, span := range spans {
+			Expect(span.Status.Code).To(Equal(codes.Unset))
+			Expect(span.Links).To(BeEmpty())
+			Expect(attributes(span)[LoaderNameKey].AsString()).To(Equal("posts"))
+			sizes = append(sizes, attributes(span)[BatchSizeKey].AsInt64())
+		}
+		Expect(sizes).To(ConsistOf(int64(2), int64(1)))
+	})
+
+	It("time the span with the loader clock", func() {
+		ctx := context.TODO()
+		clock := dataloadertest.NewFakeClock(time.Unix(100, 0))
+
+		batchLoadFn := func(ctx context.Context, keys []string) []dataloader.Result[string] {
+			clock.Advance(10 * time.Millisecond)
+			return make([]dataloader.Result[string], len(keys))
+		}
+
+		loader := dataloader.New[string, string, string](ctx, batchLoadFn,
+			dataloader.WithClock[string, string, string](clock),
+			dataloader.WithBatchScheduleFn[string, string, string](dataloader.NewTimeWindowScheduler(1*time.Second)),
+			dataloader.WithHook[string, string, string](NewHook[string, string](WithTracerProvider(provider))),
+		)
+
+		thunk := loader.Load(ctx, "a")
+		loader.Dispatch()
+		_, err := thunk.Get(ctx)
+		Expect(err).To(BeNil())
+
+		spans := exporter.GetSpans()
+		Expect(spans).To(HaveLen(1))
+		Expect(spans[0].StartTime).To(BeTemporally("==", time.Unix(100, 0)))
+		Expect(spans[0].EndTime.Sub(spans[0].StartTime)).To(Equal(10 * time.Millisecond))
+	})
+})
@@ -0,0 +1,19 @@
+package dataloader
+
+import "context"
+
+type partitionKey struct{}
+
+// ContextWithPartition returns a copy of ctx carrying partition. The loader
+// passes the partition of a batch to hooks and the batch load function this
+// way when it has a partition function.
+func ContextWithPartition(ctx context.Context, partition string) context.Context {
+	return context.WithValue(ctx, partitionKey{}, partition)
+}
+
+// PartitionFromContext returns the partition carried by ctx, and whether there
+// is one.
+func PartitionFromContext(ctx context.Context) (string, bool) {
+	partition, ok := ctx.Value(partitionKey{}).(string)
+	return partition, ok
+}
@@ -0,0 +1,70 @@
+package dataloader
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+var _ = Describe("Partition", func() {
+	It("batch keys of each partition separately", func() {
+		ctx := context.TODO()
+		mu := sync.Mutex{}
+		batches := map[string][][]string{}
+
+		batchLoadFn := func(ctx context.Context, keys []string) []Result[string] {
+			defer GinkgoRecover()
+			partition, ok := PartitionFromContext(ctx)
+			Expect(ok).To(BeTrue())
+			for _, key := range keys {
+				Expect(key).To(HavePrefix(partition + ":"))
+			}
+
+			mu.Lock()
+			batches[partition] = append(batches[partition], keys)
+			mu.Unlock()
+			return loadValues(ctx, keys)
+		}
+
+		loader := New[string, string, string](ctx, batchLoadFn,
+			WithMaxBatchSize[string, string, string](2),
+			WithBatchScheduleFn[string, string, string](NewTimeWindowScheduler(1*time.Second)),
+			WithPartitionFn[string, string, string](func(key string) string {
+				return strings.SplitN(key, ":", 2)[0]
+			}),
+		)
+
+		thunks := loader.LoadMany(ctx, []string{"a:1", "b:1", "a:2", "a:3", "b:2"})
+		loader.Dispatch()
+		for _, thunk := range thunks {
+			_, err := thunk.Get(ctx)
+			Expect(err).To(BeNil())
+		}
+
+		Expect(batches).To(HaveLen(2))
+		Expect(batches["a"]).To(ConsistOf([]string{"a:1", "a:2"}, []string{"a:3"}))
+		Expect(batches["b"]).To(Equal([][]string{{"b:1", "b:2"}}))
+	})
+
+	It("does not set a partition without partition function", func() {
+		ctx := context.TODO()
+		found := make(chan bool, 1)
+
+		batchLoadFn := func(ctx context.Context, keys []string) []Result[string] {
+			_, ok := PartitionFromContext(ctx)
+			found <- ok
+			return loadValues(ctx, keys)
+		}
+
+		loader := New[string, string, string](ctx, batchLoadFn)
+		thunk := loader.Load(ctx, "foo")
+		loader.Dispatch()
+		_, err := thunk.Get(ctx)
+		Expect(err).To(BeNil())
+		Expect(<-found).To(BeFalse())
+	})
+})
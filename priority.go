@@ -0,0 +1,59 @@
+package dataloader
+
+import (
+	"context"
+	"time"
+)
+
+// Priority orders loads by latency sensitivity. Keys of different priorities
+// are never batched together.
+type Priority int
+
+const (
+	// PriorityLow is for background work such as prefetching. Its batches
+	// wait for a longer window to collect more keys.
+	PriorityLow Priority = -1
+	// PriorityNormal is the priority of loads without one in their context.
+	PriorityNormal Priority = 0
+	// PriorityHigh is for latency critical paths. Its batches are dispatched
+	// almost immediately.
+	PriorityHigh Priority = 1
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityNormal:
+		return "normal"
+	case PriorityHigh:
+		return "high"
+	}
+	return "unknown"
+}
+
+type priorityKey struct{}
+
+// ContextWithPriority returns a copy of ctx carrying priority. Load called with
+// this context adds the key to a batch of that priority. The loader also passes
+// the priority of a batch to hooks and the batch load function this way.
+func ContextWithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+// PriorityFromContext returns the priority carried by ctx, or PriorityNormal.
+func PriorityFromContext(ctx context.Context) Priority {
+	if priority, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return priority
+	}
+	return PriorityNormal
+}
+
+// defaultPriorityScheduleFns returns the schedule functions of high and low
+// priority batches of loaders without a scheduler option.
+func defaultPriorityScheduleFns() map[Priority]BatchScheduleFn {
+	return map[Priority]BatchScheduleFn{
+		PriorityHigh: NewTimeWindowScheduler(1 * time.Millisecond),
+		PriorityLow:  NewTimeWindowScheduler(64 * time.Millisecond),
+	}
+}
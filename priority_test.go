@@ -0,0 +1,134 @@
+package dataloader
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"context"
+	"time"
+)
+
+type priorityHook struct {
+	priorities chan Priority
+}
+
+func (h *priorityHook) BeforeBatch(ctx context.Context, _ []string) {
+	h.priorities <- PriorityFromContext(ctx)
+}
+
+func (h *priorityHook) AfterBatch(context.Context, []string, []Result[string]) {}
+
+var _ = Describe("Priority", func() {
+	It("default to normal priority", func() {
+		Expect(PriorityFromContext(context.TODO())).To(Equal(PriorityNormal))
+		Expect(PriorityFromContext(ContextWithPriority(context.TODO(), PriorityHigh))).To(Equal(PriorityHigh))
+	})
+
+	It("batch keys of each priority separately and report it to hooks", func() {
+		ctx := context.TODO()
+		hook := &priorityHook{priorities: make(chan Priority, 3)}
+		batches := make(chan []string, 3)
+
+		batchLoadFn := func(ctx context.Context, keys []string) []Result[string] {
+			batches <- keys
+			return loadValues(ctx, keys)
+		}
+
+		loader := New[string, string, string](ctx, batchLoadFn,
+			WithBatchScheduleFn[string, string, string](NewTimeWindowScheduler(1*time.Second)),
+			WithPriorityScheduleFn[string, string, string](PriorityHigh, NewTimeWindowScheduler(1*time.Second)),
+			WithPriorityScheduleFn[string, string, string](PriorityLow, NewTimeWindowScheduler(1*time.Second)),
+			WithHook[string, string, string](hook),
+		)
+
+		loader.Load(ctx, "normal")
+		loader.Load(ContextWithPriority(ctx, PriorityHigh), "high")
+		loader.Load(ContextWithPriority(ctx, PriorityLow), "low")
+		loader.Load(ContextWithPriority(ctx, PriorityHigh), "high2")
+		loader.Dispatch()
+
+		Eventually(hook.priorities).Should(HaveLen(3))
+		Expect([]Priority{<-hook.priorities, <-hook.priorities, <-hook.priorities}).To(ConsistOf(PriorityNormal, PriorityHigh, PriorityLow))
+		Expect([][]string{<-batches, <-batches, <-batches}).To(ConsistOf(
+			[]string{"normal"},
+			[]string{"high", "high2"},
+			[]string{"low"},
+		))
+	})
+
+	It("dispatch high priority keys without waiting for the window", func() {
+		ctx := context.TODO()
+		loader := New[string, string, string](ctx, loadValues,
+			WithBatchScheduleFn[string, string, string](NewTimeWindowScheduler(1*time.Second)),
+			WithPriorityScheduleFn[string, string, string](PriorityHigh, NewTimeWindowScheduler(1*time.Millisecond)),
+		)
+
+		normal := loader.Load(ctx, "normal")
+
+		start := time.Now()
+		val, err := loader.Load(ContextWithPriority(ctx, PriorityHigh), "high").Get(ctx)
+		Expect(err).To(BeNil())
+		Expect(val).To(Equal("res:high"))
+		Expect(time.Since(start)).To(BeNumerically("<", 500*time.Millisecond))
+
+		loader.Dispatch()
+		_, err = normal.Get(ctx)
+		Expect(err).To(BeNil())
+	})
+
+	It("wait longer for low priority keys", func() {
+		ctx := context.TODO()
+		loader := New[string, string, string](ctx, loadValues,
+			WithBatchScheduleFn[string, string, string](NewTimeWindowScheduler(1*time.Millisecond)),
+			WithPriorityScheduleFn[string, string, string](PriorityLow, NewTimeWindowScheduler(200*time.Millisecond)),
+		)
+
+		start := time.Now()
+		low := loader.Load(ContextWithPriority(ctx, PriorityLow), "low")
+		_, err := loader.Load(ctx, "normal").Get(ctx)
+		Expect(err).To(BeNil())
+		Expect(time.Since(start)).To(BeNumerically("<", 200*time.Millisecond))
+
+		_, err = low.Get(ctx)
+		Expect(err).To(BeNil())
+		Expect(time.Since(start)).To(BeNumerically(">=", 200*time.Millisecond))
+	})
+
+	It("dispatch high priority sooner and low priority later without scheduler options", func() {
+		ctx := context.TODO()
+		hook := &priorityHook{priorities: make(chan Priority, 3)}
+		loader := New[string, string, string](ctx, loadValues, WithHook[string, string, string](hook))
+
+		start := time.Now()
+		thunks := []*Thunk[string]{
+			loader.Load(ContextWithPriority(ctx, PriorityLow), "low"),
+			loader.Load(ctx, "normal"),
+			loader.Load(ContextWithPriority(ctx, PriorityHigh), "high"),
+		}
+		for _, thunk := range thunks {
+			_, err := thunk.Get(ctx)
+			Expect(err).To(BeNil())
+		}
+		Expect(time.Since(start)).To(BeNumerically(">=", 64*time.Millisecond))
+		Expect([]Priority{<-hook.priorities, <-hook.priorities, <-hook.priorities}).To(Equal([]Priority{PriorityHigh, PriorityNormal, PriorityLow}))
+	})
+
+	It("schedule every priority with the configured scheduler", func() {
+		ctx := context.TODO()
+		scheduler := NewTickerScheduler(1*time.Second, 1*time.Millisecond)
+		loader := New[string, string, string](ctx, loadValues,
+			WithBatchScheduler[string, string, string](scheduler),
+		)
+
+		thunks := []*Thunk[string]{
+			loader.Load(ContextWithPriority(ctx, PriorityHigh), "high"),
+			loader.Load(ContextWithPriority(ctx, PriorityLow), "low"),
+		}
+		Expect(scheduler.Pending()).To(Equal(2))
+		loader.Dispatch()
+		for _, thunk := range thunks {
+			_, err := thunk.Get(ctx)
+			Expect(err).To(BeNil())
+		}
+	})
+})
@@ -0,0 +1,223 @@
+package dataloader
+
+import (
+	"context"
+	"time"
+)
+
+// NewIdleScheduler returns a scheduler which dispatches a batch once no key has
+// been added to it for idle, and at the latest maxWait after it was created.
+func NewIdleScheduler(idle time.Duration, maxWait time.Duration) BatchScheduleFn {
+	return func(ctx context.Context, batch Batch, callback func()) {
+		clock := ClockFromContext(ctx)
+		idleTimer := clock.NewTimer(idle)
+		defer idleTimer.Stop()
+		maxTimer := clock.NewTimer(maxWait)
+		defer maxTimer.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-batch.Dispatch():
+				callback()
+				return
+			case <-batch.Full():
+				callback()
+				return
+			case <-maxTimer.C():
+				callback()
+				return
+			case <-idleTimer.C():
+				callback()
+				return
+			case <-batch.Changed():
+				if !idleTimer.Stop() {
+					select {
+					case <-idleTimer.C():
+					default:
+					}
+				}
+				idleTimer.Reset(idle)
+			}
+		}
+	}
+}
+
+// NewDispatchOnGetScheduler returns a scheduler which dispatches a batch as
+// soon as Thunk.Get is called on one of its pending thunks, so synchronous
+// code does not pay for a time window. Batches nobody waits for are
+// dispatched after maxWait.
+func NewDispatchOnGetScheduler(maxWait time.Duration) BatchScheduleFn {
+	return func(ctx context.Context, batch Batch, callback func()) {
+		timer := ClockFromContext(ctx).NewTimer(maxWait)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return
+		case <-batch.Dispatch():
+			callback()
+		case <-batch.Full():
+			callback()
+		case <-batch.Waited():
+			callback()
+		case <-timer.C():
+			callback()
+		}
+	}
+}
+
+type AdaptiveSchedulerConfig struct {
+	// MinWindow and MaxWindow bound the window. They default to 1ms and 64ms.
+	MinWindow time.Duration
+	MaxWindow time.Duration
+	// InitialWindow is the window used before any batch is observed. It
+	// defaults to 16ms.
+	InitialWindow time.Duration
+	// TargetFillRatio is the wanted batch size relative to the max batch
+	// size, between 0 and 1. The window grows while batches stay below it and
+	// shrinks once they reach it. Zero disables fill ratio tuning.
+	TargetFillRatio float64
+	// LatencyBudget caps the window plus the observed BatchLoadFn latency.
+	// Zero disables latency tuning.
+	LatencyBudget time.Duration
+}
+
+// AdaptiveSchedulerStats is the tuning data of an AdaptiveScheduler. Means are
+// exponentially weighted moving averages.
+type AdaptiveSchedulerStats struct {
+	Window          time.Duration
+	Batches         uint64
+	MeanFillRatio   float64
+	MeanBatchSize   float64
+	MeanArrivalRate float64
+	MeanLoadLatency time.Duration
+}
+
+// AdaptiveScheduler is a time window scheduler which tunes its window from
+// observed key arrival and BatchLoadFn latency. Pass its Schedule method to
+// WithBatchScheduleFn.
+type AdaptiveScheduler struct {
+	config AdaptiveSchedulerConfig
+	stats  chan *AdaptiveSchedulerStats
+}
+
+const (
+	adaptiveSmoothing = 0.2
+	adaptiveGrowth    = 1.25
+	adaptiveShrink    = 0.8
+)
+
+func NewAdaptiveScheduler(config AdaptiveSchedulerConfig) *AdaptiveScheduler {
+	if config.MinWindow <= 0 {
+		config.MinWindow = 1 * time.Millisecond
+	}
+	if config.MaxWindow <= 0 {
+		config.MaxWindow = 64 * time.Millisecond
+	}
+	if config.MaxWindow < config.MinWindow {
+		config.MaxWindow = config.MinWindow
+	}
+	if config.InitialWindow <= 0 {
+		config.InitialWindow = 16 * time.Millisecond
+	}
+
+	s := &AdaptiveScheduler{
+		config: config,
+		stats:  make(chan *AdaptiveSchedulerStats, 1),
+	}
+	s.stats <- &AdaptiveSchedulerStats{Window: s.clamp(config.InitialWindow)}
+
+	return s
+}
+
+func (s *AdaptiveScheduler) Schedule(ctx context.Context, batch Batch, callback func()) {
+	stats := <-s.stats
+	window := stats.Window
+	s.stats <- stats
+
+	clock := ClockFromContext(ctx)
+	start := clock.Now()
+	timer := clock.NewTimer(window)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return
+	case <-batch.Dispatch():
+	case <-batch.Full():
+	case <-timer.C():
+	}
+
+	loadStart := clock.Now()
+	callback()
+	s.observe(batch, loadStart.Sub(start), clock.Now().Sub(loadStart))
+}
+
+// Stats returns the current window and the data it was tuned from.
+func (s *AdaptiveScheduler) Stats() AdaptiveSchedulerStats {
+	stats := <-s.stats
+	snapshot := *stats
+	s.stats <- stats
+	return snapshot
+}
+
+func (s *AdaptiveScheduler) observe(batch Batch, waited time.Duration, latency time.Duration) {
+	size, fill := batch.Len(), 1.0
+	if batch.MaxSize() > 0 {
+		fill = float64(size) / float64(batch.MaxSize())
+	}
+
+	rate := 0.0
+	if waited > 0 {
+		rate = float64(size) / waited.Seconds()
+	}
+
+	stats := <-s.stats
+	if stats.Batches == 0 {
+		stats.MeanFillRatio = fill
+		stats.MeanBatchSize = float64(size)
+		stats.MeanArrivalRate = rate
+		stats.MeanLoadLatency = latency
+	} else {
+		stats.MeanFillRatio = smooth(stats.MeanFillRatio, fill)
+		stats.MeanBatchSize = smooth(stats.MeanBatchSize, float64(size))
+		stats.MeanArrivalRate = smooth(stats.MeanArrivalRate, rate)
+		stats.MeanLoadLatency = time.Duration(smooth(float64(stats.MeanLoadLatency), float64(latency)))
+	}
+	stats.Batches++
+	stats.Window = s.tune(stats)
+	s.stats <- stats
+}
+
+func (s *AdaptiveScheduler) tune(stats *AdaptiveSchedulerStats) time.Duration {
+	window := stats.Window
+
+	if s.config.TargetFillRatio > 0 {
+		if stats.MeanFillRatio < s.config.TargetFillRatio {
+			window = time.Duration(float64(window) * adaptiveGrowth)
+		} else {
+			window = time.Duration(float64(window) * adaptiveShrink)
+		}
+	}
+
+	if s.config.LatencyBudget > 0 && window+stats.MeanLoadLatency > s.config.LatencyBudget {
+		window = s.config.LatencyBudget - stats.MeanLoadLatency
+	}
+
+	return s.clamp(window)
+}
+
+func (s *AdaptiveScheduler) clamp(window time.Duration) time.Duration {
+	if window < s.config.MinWindow {
+		return s.config.MinWindow
+	}
+	if window > s.config.MaxWindow {
+		return s.config.MaxWindow
+	}
+	return window
+}
+
+func smooth(mean float64, sample float64) float64 {
+	return mean + adaptiveSmoothing*(sample-mean)
+}
@@ -0,0 +1,309 @@
+package dataloader
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"context"
+	"reflect"
+	"time"
+)
+
+type sizedMockBatch struct {
+	dispatch chan struct{}
+	size     int
+	maxSize  int
+}
+
+func newSizedMockBatch(size int, maxSize int) *sizedMockBatch {
+	b := &sizedMockBatch{dispatch: make(chan struct{}), size: size, maxSize: maxSize}
+	close(b.dispatch)
+	return b
+}
+
+func (b *sizedMockBatch) Full() <-chan struct{}     { return make(<-chan struct{}) }
+func (b *sizedMockBatch) Dispatch() <-chan struct{} { return b.dispatch }
+func (b *sizedMockBatch) Changed() <-chan struct{}  { return make(<-chan struct{}) }
+func (b *sizedMockBatch) Waited() <-chan struct{}   { return make(<-chan struct{}) }
+func (b *sizedMockBatch) Len() int                  { return b.size }
+func (b *sizedMockBatch) MaxSize() int              { return b.maxSize }
+func (b *sizedMockBatch) CreatedAt() time.Time      { return time.Time{} }
+
+type changingMockBatch struct {
+	changed chan struct{}
+}
+
+func (b *changingMockBatch) Full() <-chan struct{}     { return make(<-chan struct{}) }
+func (b *changingMockBatch) Dispatch() <-chan struct{} { return make(<-chan struct{}) }
+func (b *changingMockBatch) Changed() <-chan struct{}  { return b.changed }
+func (b *changingMockBatch) Waited() <-chan struct{}   { return make(<-chan struct{}) }
+func (b *changingMockBatch) Len() int                  { return 0 }
+func (b *changingMockBatch) MaxSize() int              { return 0 }
+func (b *changingMockBatch) CreatedAt() time.Time      { return time.Time{} }
+
+var _ = Describe("Batch", func() {
+	It("expose metadata to custom scheduler", func() {
+		ctx := context.TODO()
+		loaded := make(chan []string, 1)
+		batchLoadFn := func(ctx context.Context, keys []string) []Result[string] {
+			loaded <- append([]string(nil), keys...)
+			return make([]Result[string], len(keys))
+		}
+
+		// dispatch when half full or 1s old
+		observed := make(chan Batch, 1)
+		scheduleFn := func(ctx context.Context, batch Batch, callback func()) {
+			observed <- batch
+			timer := time.NewTimer(time.Until(batch.CreatedAt().Add(1 * time.Second)))
+			defer timer.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-timer.C:
+					callback()
+					return
+				case <-batch.Changed():
+					if batch.Len()*2 >= batch.MaxSize() {
+						callback()
+						return
+					}
+				}
+			}
+		}
+
+		loader := New[string, string, string](ctx, batchLoadFn,
+			WithMaxBatchSize[string, string, string](4),
+			WithBatchScheduleFn[string, string, string](scheduleFn),
+		)
+		start := time.Now()
+		loader.Load(ctx, "foo")
+		Consistently(loaded, 50*time.Millisecond).ShouldNot(Receive())
+		loader.Load(ctx, "bar")
+
+		Eventually(loaded).Should(Receive(Equal([]string{"foo", "bar"})))
+		batch := <-observed
+		Expect(batch.Len()).To(Equal(2))
+		Expect(batch.MaxSize()).To(Equal(4))
+		Expect(batch.CreatedAt()).To(BeTemporally("~", start, 50*time.Millisecond))
+	})
+})
+
+var _ = Describe("NewIdleScheduler", func() {
+	It("dispatch after idle interval without new keys", func() {
+		scheduler := NewIdleScheduler(100*time.Millisecond, 1*time.Second)
+		batch := &changingMockBatch{changed: make(chan struct{})}
+		dispatched := make(chan time.Time, 1)
+
+		go scheduler(context.TODO(), batch, func() { dispatched <- time.Now() })
+
+		var last time.Time
+		for i := 0; i < 3; i++ {
+			time.Sleep(50 * time.Millisecond)
+			batch.changed <- struct{}{}
+			last = time.Now()
+		}
+		Consistently(dispatched, 80*time.Millisecond).ShouldNot(Receive())
+
+		var at time.Time
+		Eventually(dispatched).Should(Receive(&at))
+		Expect(at.Sub(last)).To(BeNumerically(">=", 100*time.Millisecond))
+	})
+
+	It("dispatch after max wait even if keys keep arriving", func() {
+		scheduler := NewIdleScheduler(100*time.Millisecond, 200*time.Millisecond)
+		batch := &changingMockBatch{changed: make(chan struct{})}
+		dispatched := make(chan struct{})
+		start := time.Now()
+
+		go scheduler(context.TODO(), batch, func() { close(dispatched) })
+
+		for {
+			select {
+			case <-dispatched:
+				Expect(time.Since(start)).To(BeNumerically("<", 300*time.Millisecond))
+				return
+			case <-time.After(50 * time.Millisecond):
+				select {
+				case batch.changed <- struct{}{}:
+				case <-dispatched:
+					Expect(time.Since(start)).To(BeNumerically("<", 300*time.Millisecond))
+					return
+				}
+			}
+		}
+	})
+
+	It("can cancel", func() {
+		scheduler := NewIdleScheduler(100*time.Millisecond, 1*time.Second)
+		runned := false
+
+		ctx, cancel := context.WithCancel(context.TODO())
+		cancel()
+		scheduler(ctx, &changingMockBatch{}, func() { runned = true })
+		Expect(runned).To(BeFalse())
+	})
+
+	It("batch loader keys until idle", func() {
+		ctx := context.TODO()
+		loadKeys := [][]string{}
+		batchLoadFn := func(ctx context.Context, keys []string) []Result[string] {
+			loadKeys = append(loadKeys, append([]string(nil), keys...))
+			return make([]Result[string], len(keys))
+		}
+
+		loader := New[string, string, string](ctx, batchLoadFn,
+			WithBatchScheduleFn[string, string, string](NewIdleScheduler(200*time.Millisecond, 2*time.Second)),
+		)
+		first := loader.Load(ctx, "foo")
+		time.Sleep(50 * time.Millisecond)
+		second := loader.Load(ctx, "bar")
+
+		for _, thunk := range []*Thunk[string]{first, second} {
+			_, err := thunk.Get(ctx)
+			Expect(err).To(BeNil())
+		}
+		Expect(loadKeys).To(Equal([][]string{{"foo", "bar"}}))
+	})
+})
+
+var _ = Describe("NewDispatchOnGetScheduler", func() {
+	It("dispatch as soon as someone waits", func() {
+		ctx := context.TODO()
+		loadKeys := [][]string{}
+		batchLoadFn := func(ctx context.Context, keys []string) []Result[string] {
+			loadKeys = append(loadKeys, append([]string(nil), keys...))
+			result := make([]Result[string], len(keys))
+			for index, key := range keys {
+				result[index] = Result[string]{Value: "res:" + key}
+			}
+			return result
+		}
+
+		loader := New[string, string, string](ctx, batchLoadFn,
+			WithBatchScheduleFn[string, string, string](NewDispatchOnGetScheduler(1*time.Second)),
+		)
+
+		start := time.Now()
+		thunks := loader.LoadMany(ctx, []string{"foo", "bar"})
+		for _, thunk := range thunks {
+			_, err := thunk.Get(ctx)
+			Expect(err).To(BeNil())
+		}
+		Expect(time.Since(start)).To(BeNumerically("<", 500*time.Millisecond))
+		Expect(loadKeys).To(Equal([][]string{{"foo", "bar"}}))
+	})
+
+	It("release batch from thunks once dispatched", func() {
+		ctx := context.TODO()
+		batchLoadFn := func(ctx context.Context, keys []string) []Result[string] {
+			return make([]Result[string], len(keys))
+		}
+
+		loader := New[string, string, string](ctx, batchLoadFn,
+			WithBatchScheduleFn[string, string, string](NewDispatchOnGetScheduler(1*time.Second)),
+		)
+
+		thunk := loader.Load(ctx, "foo")
+		_, err := thunk.Get(ctx)
+		Expect(err).To(BeNil())
+		waited := thunk.waited.Load()
+		Expect(reflect.ValueOf(waited).Pointer()).To(Equal(reflect.ValueOf(noWait).Pointer()))
+	})
+
+	It("dispatch after max wait without waiter", func() {
+		ctx := context.TODO()
+		loaded := make(chan []string, 1)
+		batchLoadFn := func(ctx context.Context, keys []string) []Result[string] {
+			loaded <- keys
+			return make([]Result[string], len(keys))
+		}
+
+		loader := New[string, string, string](ctx, batchLoadFn,
+			WithBatchScheduleFn[string, string, string](NewDispatchOnGetScheduler(50*time.Millisecond)),
+		)
+		loader.Load(ctx, "foo")
+
+		Eventually(loaded).Should(Receive(Equal([]string{"foo"})))
+	})
+})
+
+var _ = Describe("AdaptiveScheduler", func() {
+	It("grow window while batches stay below target fill ratio", func() {
+		scheduler := NewAdaptiveScheduler(AdaptiveSchedulerConfig{
+			InitialWindow:   10 * time.Millisecond,
+			MaxWindow:       20 * time.Millisecond,
+			TargetFillRatio: 0.5,
+		})
+
+		scheduler.Schedule(context.TODO(), newSizedMockBatch(1, 100), func() {})
+		Expect(scheduler.Stats().Window).To(Equal(12500 * time.Microsecond))
+
+		for i := 0; i < 10; i++ {
+			scheduler.Schedule(context.TODO(), newSizedMockBatch(1, 100), func() {})
+		}
+		stats := scheduler.Stats()
+		Expect(stats.Window).To(Equal(20 * time.Millisecond))
+		Expect(stats.Batches).To(Equal(uint64(11)))
+		Expect(stats.MeanBatchSize).To(BeNumerically("~", 1))
+	})
+
+	It("shrink window once batches reach target fill ratio", func() {
+		scheduler := NewAdaptiveScheduler(AdaptiveSchedulerConfig{
+			InitialWindow:   10 * time.Millisecond,
+			MinWindow:       5 * time.Millisecond,
+			TargetFillRatio: 0.5,
+		})
+
+		for i := 0; i < 10; i++ {
+			scheduler.Schedule(context.TODO(), newSizedMockBatch(100, 100), func() {})
+		}
+		stats := scheduler.Stats()
+		Expect(stats.Window).To(Equal(5 * time.Millisecond))
+		Expect(stats.MeanFillRatio).To(BeNumerically("~", 1))
+	})
+
+	It("keep window within latency budget", func() {
+		scheduler := NewAdaptiveScheduler(AdaptiveSchedulerConfig{
+			InitialWindow: 30 * time.Millisecond,
+			LatencyBudget: 40 * time.Millisecond,
+		})
+
+		scheduler.Schedule(context.TODO(), newSizedMockBatch(1, 100), func() { time.Sleep(20 * time.Millisecond) })
+		stats := scheduler.Stats()
+		Expect(stats.MeanLoadLatency).To(BeNumerically(">=", 20*time.Millisecond))
+		Expect(stats.Window).To(BeNumerically("<=", 20*time.Millisecond))
+	})
+
+	It("can cancel", func() {
+		scheduler := NewAdaptiveScheduler(AdaptiveSchedulerConfig{})
+		runned := false
+
+		ctx, cancel := context.WithCancel(context.TODO())
+		cancel()
+		scheduler.Schedule(ctx, &MockBatch{}, func() { runned = true })
+
+		Expect(runned).To(BeFalse())
+		Expect(scheduler.Stats().Batches).To(Equal(uint64(0)))
+	})
+
+	It("observe loader batches", func() {
+		ctx := context.TODO()
+		scheduler := NewAdaptiveScheduler(AdaptiveSchedulerConfig{TargetFillRatio: 0.5})
+		batchLoadFn := func(ctx context.Context, keys []string) []Result[string] {
+			return make([]Result[string], len(keys))
+		}
+
+		loader := New[string, string, string](ctx, batchLoadFn,
+			WithMaxBatchSize[string, string, string](4),
+			WithBatchScheduleFn[string, string, string](scheduler.Schedule),
+		)
+		for _, thunk := range loader.LoadMany(ctx, []string{"foo", "bar"}) {
+			_, err := thunk.Get(ctx)
+			Expect(err).To(BeNil())
+		}
+
+		Eventually(func() uint64 { return scheduler.Stats().Batches }).Should(Equal(uint64(1)))
+		Expect(scheduler.Stats().MeanFillRatio).To(Equal(0.5))
+	})
+})
@@ -0,0 +1,128 @@
+package dataloader
+
+import (
+	"context"
+	"time"
+)
+
+// Scope is the closest Go equivalent of the end of an event loop tick. A
+// GraphQL executor creates one per execution, registers its resolver
+// goroutines with Add and Done, and either calls Tick when a resolver level
+// finishes or lets the scope tick by itself once every registered goroutine is
+// blocked in Thunk.Get. Loaders scheduled with NewScopeScheduler dispatch on
+// each tick instead of waiting for a time window.
+type Scope struct {
+	state chan *scopeState
+}
+
+type scopeState struct {
+	active  int
+	waiting int
+	tick    chan struct{}
+}
+
+type scopeKey struct{}
+
+func NewScope() *Scope {
+	s := &Scope{
+		state: make(chan *scopeState, 1),
+	}
+	s.state <- &scopeState{tick: make(chan struct{})}
+	return s
+}
+
+// ContextWithScope returns a copy of ctx carrying scope. Thunk.Get called
+// with this context counts as waiting in the scope.
+func ContextWithScope(ctx context.Context, scope *Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+func scopeFromContext(ctx context.Context) *Scope {
+	scope, _ := ctx.Value(scopeKey{}).(*Scope)
+	return scope
+}
+
+// Add registers delta resolver goroutines, like sync.WaitGroup.
+func (s *Scope) Add(delta int) {
+	state := <-s.state
+	state.active += delta
+	s.tickIfIdle(state)
+	s.state <- state
+}
+
+// Done unregisters a resolver goroutine.
+func (s *Scope) Done() {
+	s.Add(-1)
+}
+
+// Tick dispatches every batch currently scheduled in the scope.
+func (s *Scope) Tick() {
+	state := <-s.state
+	s.tick(state)
+	s.state <- state
+}
+
+// next returns a channel closed on the next tick. It is already closed if
+// every goroutine is waiting, so a batch created right before the last
+// goroutine blocked is not left behind.
+func (s *Scope) next() <-chan struct{} {
+	state := <-s.state
+	tick := state.tick
+	if s.idle(state) {
+		closed := make(chan struct{})
+		close(closed)
+		tick = closed
+	}
+	s.state <- state
+
+	return tick
+}
+
+func (s *Scope) wait() {
+	state := <-s.state
+	state.waiting++
+	s.tickIfIdle(state)
+	s.state <- state
+}
+
+func (s *Scope) resume() {
+	state := <-s.state
+	state.waiting--
+	s.state <- state
+}
+
+func (s *Scope) idle(state *scopeState) bool {
+	return state.waiting > 0 && state.waiting >= state.active
+}
+
+func (s *Scope) tickIfIdle(state *scopeState) {
+	if s.idle(state) {
+		s.tick(state)
+	}
+}
+
+func (s *Scope) tick(state *scopeState) {
+	close(state.tick)
+	state.tick = make(chan struct{})
+}
+
+// NewScopeScheduler returns a scheduler which dispatches a batch on the next
+// tick of scope, or at the latest after maxWait.
+func NewScopeScheduler(scope *Scope, maxWait time.Duration) BatchScheduleFn {
+	return func(ctx context.Context, batch Batch, callback func()) {
+		timer := ClockFromContext(ctx).NewTimer(maxWait)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return
+		case <-batch.Dispatch():
+			callback()
+		case <-batch.Full():
+			callback()
+		case <-scope.next():
+			callback()
+		case <-timer.C():
+			callback()
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package dataloader
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var _ = Describe("Scope", func() {
+	var (
+		loadKeys    chan []string
+		batchLoadFn BatchLoadFn[string, string]
+	)
+
+	BeforeEach(func() {
+		loadKeys = make(chan []string, 10)
+		batchLoadFn = func(ctx context.Context, keys []string) []Result[string] {
+			loadKeys <- append([]string(nil), keys...)
+			result := make([]Result[string], len(keys))
+			for index, key := range keys {
+				result[index] = Result[string]{Value: "res:" + key}
+			}
+			return result
+		}
+	})
+
+	It("dispatch on manual tick", func() {
+		ctx := context.TODO()
+		scope := NewScope()
+		loader := New[string, string, string](ctx, batchLoadFn,
+			WithBatchScheduleFn[string, string, string](NewScopeScheduler(scope, 1*time.Second)),
+		)
+
+		start := time.Now()
+		thunks := loader.LoadMany(ctx, []string{"foo", "bar"})
+		Eventually(func() bool {
+			scope.Tick()
+			select {
+			case keys := <-loadKeys:
+				Expect(keys).To(Equal([]string{"foo", "bar"}))
+				return true
+			default:
+				return false
+			}
+		}).Should(BeTrue())
+
+		for _, thunk := range thunks {
+			_, err := thunk.Get(ctx)
+			Expect(err).To(BeNil())
+		}
+		Expect(time.Since(start)).To(BeNumerically("<", 500*time.Millisecond))
+	})
+
+	It("tick once every resolver goroutine waits", func() {
+		scope := NewScope()
+		ctx := ContextWithScope(context.TODO(), scope)
+		loader := New[string, string, string](ctx, batchLoadFn,
+			WithBatchScheduleFn[string, string, string](NewScopeScheduler(scope, 1*time.Second)),
+		)
+
+		start := time.Now()
+		wg := &sync.WaitGroup{}
+		scope.Add(3)
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			key := fmt.Sprintf("key%d", i)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				defer scope.Done()
+
+				val, err := loader.Load(ctx, key).Get(ctx)
+				Expect(err).To(BeNil())
+				Expect(val).To(Equal("res:" + key))
+			}()
+		}
+		wg.Wait()
+
+		Expect(time.Since(start)).To(BeNumerically("<", 500*time.Millisecond))
+		Expect(loadKeys).To(Receive(ConsistOf("key0", "key1", "key2")))
+		Expect(loadKeys).NotTo(Receive())
+	})
+
+	It("tick when an unregistered goroutine waits", func() {
+		scope := NewScope()
+		ctx := ContextWithScope(context.TODO(), scope)
+		loader := New[string, string, string](ctx, batchLoadFn,
+			WithBatchScheduleFn[string, string, string](NewScopeScheduler(scope, 1*time.Second)),
+		)
+
+		start := time.Now()
+		val, err := loader.Load(ctx, "foo").Get(ctx)
+		Expect(err).To(BeNil())
+		Expect(val).To(Equal("res:foo"))
+		Expect(time.Since(start)).To(BeNumerically("<", 500*time.Millisecond))
+	})
+
+	It("fall back to max wait", func() {
+		ctx := context.TODO()
+		scope := NewScope()
+		loader := New[string, string, string](ctx, batchLoadFn,
+			WithBatchScheduleFn[string, string, string](NewScopeScheduler(scope, 50*time.Millisecond)),
+		)
+
+		val, err := loader.Load(ctx, "foo").Get(ctx)
+		Expect(err).To(BeNil())
+		Expect(val).To(Equal("res:foo"))
+	})
+})
@@ -0,0 +1,150 @@
+//go:build go1.21
+
+// Package sloghook logs dataloader batches with log/slog.
+package sloghook
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/yckao/go-dataloader"
+)
+
+// Hook is a dataloader.Hook logging the start and end of every batch, with its
+// size, duration and errors. Records are logged with the batch context, so
+// handlers can add request attributes from it.
+type Hook[K any, V any] struct {
+	logger *slog.Logger
+	config config
+}
+
+type config struct {
+	level      slog.Level
+	errorLevel slog.Level
+	keySample  int
+	name       string
+}
+
+type Option func(*config)
+
+// WithLevel sets the level of batch start and end records. It defaults to
+// slog.LevelDebug.
+func WithLevel(level slog.Level) Option {
+	return func(c *config) {
+		c.level = level
+	}
+}
+
+// WithErrorLevel sets the level of records about failed keys and batches. It
+// defaults to slog.LevelError.
+func WithErrorLevel(level slog.Level) Option {
+	return func(c *config) {
+		c.errorLevel = level
+	}
+}
+
+// WithKeySample sets how many keys of a batch are logged. It defaults to 10,
+// and zero logs no keys.
+func WithKeySample(n int) Option {
+	return func(c *config) {
+		c.keySample = n
+	}
+}
+
+// WithLoaderName adds the loader name to every record. It defaults to the name
+// set with dataloader.WithName.
+func WithLoaderName(name string) Option {
+	return func(c *config) {
+		c.name = name
+	}
+}
+
+func NewHook[K any, V any](logger *slog.Logger, options ...Option) *Hook[K, V] {
+	c := config{
+		level:      slog.LevelDebug,
+		errorLevel: slog.LevelError,
+		keySample:  10,
+	}
+	for _, option := range options {
+		option(&c)
+	}
+
+	if c.name != "" {
+		logger = logger.With("loader", c.name)
+	}
+
+	return &Hook[K, V]{
+		logger: logger,
+		config: c,
+	}
+}
+
+func (h *Hook[K, V]) BeforeBatch(ctx context.Context, keys []K) {
+	if !h.logger.Enabled(ctx, h.config.level) {
+		return
+	}
+	attrs := append(h.attrs(ctx, slog.Int("size", len(keys))), h.sample(keys)...)
+	h.logger.LogAttrs(ctx, h.config.level, "dataloader batch start", attrs...)
+}
+
+func (h *Hook[K, V]) AfterBatch(ctx context.Context, keys []K, results []dataloader.Result[V]) {
+	attrs := h.attrs(ctx, slog.Int("size", len(keys)))
+	if start, ok := dataloader.BatchStartFromContext(ctx); ok {
+		attrs = append(attrs, slog.Duration("duration", dataloader.ClockFromContext(ctx).Now().Sub(start)))
+	}
+
+	errors := 0
+	var first error
+	for _, res := range results {
+		if res.Error != nil {
+			if first == nil {
+				first = res.Error
+			}
+			errors++
+		}
+	}
+
+	level := h.config.level
+	if errors != 0 {
+		level = h.config.errorLevel
+		attrs = append(attrs, slog.Int("errors", errors), slog.Any("error", first))
+	}
+	if !h.logger.Enabled(ctx, level) {
+		return
+	}
+	h.logger.LogAttrs(ctx, level, "dataloader batch end", append(attrs, h.sample(keys)...)...)
+}
+
+// OnBatchError logs batches failing as a whole, see dataloader.BatchErrorHook.
+func (h *Hook[K, V]) OnBatchError(ctx context.Context, keys []K, err error) {
+	if !h.logger.Enabled(ctx, h.config.errorLevel) {
+		return
+	}
+	attrs := append(h.attrs(ctx, slog.Int("size", len(keys)), slog.Any("error", err)), h.sample(keys)...)
+	h.logger.LogAttrs(ctx, h.config.errorLevel, "dataloader batch failed", attrs...)
+}
+
+// attrs returns attrs, preceded by the loader name from ctx unless one was
+// configured with WithLoaderName.
+func (h *Hook[K, V]) attrs(ctx context.Context, attrs ...slog.Attr) []slog.Attr {
+	if h.config.name == "" {
+		if name, ok := dataloader.LoaderNameFromContext(ctx); ok {
+			return append([]slog.Attr{slog.String("loader", name)}, attrs...)
+		}
+	}
+	return attrs
+}
+
+// sample returns the logged keys, and how many were left out.
+func (h *Hook[K, V]) sample(keys []K) []slog.Attr {
+	if h.config.keySample <= 0 {
+		return nil
+	}
+	if len(keys) <= h.config.keySample {
+		return []slog.Attr{slog.Any("keys", keys)}
+	}
+	return []slog.Attr{
+		slog.Any("keys", keys[:h.config.keySample]),
+		slog.Int("keys_omitted", len(keys)-h.config.keySample),
+	}
+}
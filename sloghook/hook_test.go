@@ -0,0 +1,154 @@
+//go:build go1.21
+
+package sloghook
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yckao/go-dataloader"
+	"github.com/yckao/go-dataloader/dataloadertest"
+)
+
+func TestSloghook(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Sloghook Suite")
+}
+
+type requestKey struct{}
+
+// requestHandler adds the request id carried by the context to records.
+type requestHandler struct {
+	slog.Handler
+}
+
+func (h requestHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id, ok := ctx.Value(requestKey{}).(string); ok {
+		record.AddAttrs(slog.String("request", id))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h requestHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return requestHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func records(buf *bytes.Buffer) []map[string]interface{} {
+	result := []map[string]interface{}{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		record := map[string]interface{}{}
+		Expect(json.Unmarshal([]byte(line), &record)).To(Succeed())
+		result = append(result, record)
+	}
+	return result
+}
+
+func batchLoadFn(ctx context.Context, keys []string) []dataloader.Result[string] {
+	results := make([]dataloader.Result[string], len(keys))
+	for index, key := range keys {
+		if key == "bad" {
+			results[index] = dataloader.Result[string]{Error: errors.New("bad key")}
+		} else {
+			results[index] = dataloader.Result[string]{Value: key}
+		}
+	}
+	return results
+}
+
+var _ = Describe("Hook", func() {
+	It("log batch start and end with sampled keys through the context", func() {
+		buf := &bytes.Buffer{}
+		logger := slog.New(requestHandler{slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})})
+		ctx := context.WithValue(context.TODO(), requestKey{}, "req-1")
+
+		loader := dataloader.New[string, string, string](ctx, batchLoadFn,
+			dataloader.WithBatchScheduleFn[string, string, string](dataloader.NewTimeWindowScheduler(1*time.Second)),
+			dataloader.WithHook[string, string, string](NewHook[string, string](logger,
+				WithKeySample(2),
+				WithLoaderName("users"),
+			)),
+		)
+
+		thunks := loader.LoadMany(ctx, []string{"a", "b", "bad"})
+		loader.Dispatch()
+		for _, thunk := range thunks {
+			thunk.Get(ctx)
+		}
+
+		logged := records(buf)
+		Expect(logged).To(HaveLen(2))
+
+		Expect(logged[0]).To(HaveKeyWithValue("msg", "dataloader batch start"))
+		Expect(logged[0]).To(HaveKeyWithValue("level", "DEBUG"))
+		Expect(logged[0]).To(HaveKeyWithValue("loader", "users"))
+		Expect(logged[0]).To(HaveKeyWithValue("request", "req-1"))
+		Expect(logged[0]).To(HaveKeyWithValue("size", 3.0))
+		Expect(logged[0]).To(HaveKeyWithValue("keys", []interface{}{"a", "b"}))
+		Expect(logged[0]).To(HaveKeyWithValue("keys_omitted", 1.0))
+
+		Expect(logged[1]).To(HaveKeyWithValue("msg", "dataloader batch end"))
+		Expect(logged[1]).To(HaveKeyWithValue("level", "ERROR"))
+		Expect(logged[1]).To(HaveKeyWithValue("errors", 1.0))
+		Expect(logged[1]).To(HaveKeyWithValue("error", "bad key"))
+		Expect(logged[1]).To(HaveKey("duration"))
+	})
+
+	It("measure duration with the loader clock", func() {
+		buf := &bytes.Buffer{}
+		logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		ctx := context.TODO()
+		clock := dataloadertest.NewFakeClock(time.Unix(0, 0))
+
+		slowBatchLoadFn := func(ctx context.Context, keys []string) []dataloader.Result[string] {
+			clock.Advance(10 * time.Millisecond)
+			return batchLoadFn(ctx, keys)
+		}
+
+		loader := dataloader.New[string, string, string](ctx, slowBatchLoadFn,
+			dataloader.WithClock[string, string, string](clock),
+			dataloader.WithBatchScheduleFn[string, string, string](dataloader.NewTimeWindowScheduler(1*time.Second)),
+			dataloader.WithHook[string, string, string](NewHook[string, string](logger)),
+		)
+
+		thunk := loader.Load(ctx, "a")
+		loader.Dispatch()
+		_, err := thunk.Get(ctx)
+		Expect(err).To(BeNil())
+
+		logged := records(buf)
+		Expect(logged).To(HaveLen(2))
+		Expect(logged[1]).To(HaveKeyWithValue("duration", float64(10*time.Millisecond)))
+	})
+
+	It("log nothing below the configured level", func() {
+		buf := &bytes.Buffer{}
+		logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+		ctx := context.TODO()
+
+		loader := dataloader.New[string, string, string](ctx, batchLoadFn,
+			dataloader.WithHook[string, string, string](NewHook[string, string](logger, WithKeySample(0))),
+		)
+		_, err := loader.Load(ctx, "a").Get(ctx)
+		Expect(err).To(BeNil())
+		Expect(buf.Len()).To(BeZero())
+
+		loader = dataloader.New[string, string, string](ctx, batchLoadFn,
+			dataloader.WithHook[string, string, string](NewHook[string, string](logger, WithLevel(slog.LevelInfo), WithKeySample(0))),
+		)
+		_, err = loader.Load(ctx, "a").Get(ctx)
+		Expect(err).To(BeNil())
+
+		logged := records(buf)
+		Expect(logged).To(HaveLen(2))
+		Expect(logged[1]).ToNot(HaveKey("keys"))
+	})
+})
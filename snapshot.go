@@ -0,0 +1,202 @@
+package dataloader
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+const (
+	snapshotMagic      = "GODLSNAP"
+	snapshotVersion    = 1
+	maxSnapshotRecord  = 64 << 20
+	snapshotHeaderSize = len(snapshotMagic) + 1
+)
+
+var (
+	ErrCorruptSnapshot            = errors.New("cache snapshot is corrupt")
+	ErrUnsupportedSnapshotVersion = errors.New("cache snapshot version is not supported")
+	ErrUnrangeableCache           = errors.New("cache map cannot be enumerated for snapshot")
+)
+
+// SnapshotCodec encodes a single cache entry of a snapshot.
+type SnapshotCodec[C comparable, V any] interface {
+	Marshal(key C, val V) ([]byte, error)
+	Unmarshal(data []byte) (C, V, error)
+}
+
+type gobSnapshotCodec[C comparable, V any] struct{}
+
+type gobSnapshotEntry[C comparable, V any] struct {
+	Key   C
+	Value V
+}
+
+// NewGobSnapshotCodec returns a SnapshotCodec based on encoding/gob.
+func NewGobSnapshotCodec[C comparable, V any]() SnapshotCodec[C, V] {
+	return gobSnapshotCodec[C, V]{}
+}
+
+func (gobSnapshotCodec[C, V]) Marshal(key C, val V) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	err := gob.NewEncoder(buf).Encode(gobSnapshotEntry[C, V]{Key: key, Value: val})
+	return buf.Bytes(), err
+}
+
+func (gobSnapshotCodec[C, V]) Unmarshal(data []byte) (C, V, error) {
+	entry := gobSnapshotEntry[C, V]{}
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&entry)
+	return entry.Key, entry.Value, err
+}
+
+// CacheSnapshot saves the resolved entries of a loader cache and restores
+// them into another one, for example to warm a loader at startup. It must not
+// be used while a loader is using the same cache map.
+type CacheSnapshot[C comparable, V any] struct {
+	cacheMap CacheMap[C, *Thunk[V]]
+	codec    SnapshotCodec[C, V]
+}
+
+func NewCacheSnapshot[C comparable, V any](cacheMap CacheMap[C, *Thunk[V]], codec SnapshotCodec[C, V]) *CacheSnapshot[C, V] {
+	return &CacheSnapshot[C, V]{
+		cacheMap: cacheMap,
+		codec:    codec,
+	}
+}
+
+// Snapshot writes every resolved entry of the cache to w. Pending thunks and
+// thunks holding an error are skipped. The cache map must implement
+// IterableCacheMap.
+func (s *CacheSnapshot[C, V]) Snapshot(w io.Writer) error {
+	iterable, ok := s.cacheMap.(IterableCacheMap[C, *Thunk[V]])
+	if !ok {
+		return ErrUnrangeableCache
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString(snapshotMagic)
+	bw.WriteByte(snapshotVersion)
+
+	var err error
+	rangeErr := iterable.Range(context.Background(), func(key C, thunk *Thunk[V]) bool {
+		if thunk == nil {
+			return true
+		}
+		data, ok := thunk.peek()
+		if !ok || data.err != nil {
+			return true
+		}
+
+		var record []byte
+		record, err = s.codec.Marshal(key, data.value)
+		if err != nil {
+			return false
+		}
+		err = writeSnapshotRecord(bw, record)
+		return err == nil
+	})
+	if rangeErr != nil {
+		return rangeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := writeSnapshotRecord(bw, nil); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// Restore reads a snapshot written by Snapshot from r and stores every entry
+// in the cache as a resolved thunk. The whole snapshot is validated before the
+// cache is touched, so a corrupt snapshot leaves the cache unchanged.
+func (s *CacheSnapshot[C, V]) Restore(r io.Reader) error {
+	br := bufio.NewReader(r)
+
+	header := make([]byte, snapshotHeaderSize)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return fmt.Errorf("%w: %v", ErrCorruptSnapshot, err)
+	}
+	if string(header[:len(snapshotMagic)]) != snapshotMagic {
+		return fmt.Errorf("%w: bad header", ErrCorruptSnapshot)
+	}
+	if header[len(snapshotMagic)] != snapshotVersion {
+		return fmt.Errorf("%w: %d", ErrUnsupportedSnapshotVersion, header[len(snapshotMagic)])
+	}
+
+	keys := []C{}
+	vals := []V{}
+	for {
+		record, err := readSnapshotRecord(br)
+		if err != nil {
+			return err
+		}
+		if record == nil {
+			break
+		}
+
+		key, val, err := s.codec.Unmarshal(record)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrCorruptSnapshot, err)
+		}
+		keys = append(keys, key)
+		vals = append(vals, val)
+	}
+
+	ctx := context.Background()
+	for index, key := range keys {
+		thunk := NewThunk[V]()
+		thunk.set(ctx, vals[index])
+		if err := s.cacheMap.Set(ctx, key, thunk); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeSnapshotRecord writes a length prefixed record followed by its CRC-32
+// checksum. An empty record marks the end of the snapshot.
+func writeSnapshotRecord(w *bufio.Writer, record []byte) error {
+	size := make([]byte, binary.MaxVarintLen64)
+	w.Write(size[:binary.PutUvarint(size, uint64(len(record)))])
+	if len(record) == 0 {
+		return nil
+	}
+
+	w.Write(record)
+	sum := make([]byte, 4)
+	binary.BigEndian.PutUint32(sum, crc32.ChecksumIEEE(record))
+	_, err := w.Write(sum)
+	return err
+}
+
+func readSnapshotRecord(r *bufio.Reader) ([]byte, error) {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptSnapshot, err)
+	}
+	if size == 0 {
+		return nil, nil
+	}
+	if size > maxSnapshotRecord {
+		return nil, fmt.Errorf("%w: record of %d bytes", ErrCorruptSnapshot, size)
+	}
+
+	record := make([]byte, size+4)
+	if _, err := io.ReadFull(r, record); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptSnapshot, err)
+	}
+	if crc32.ChecksumIEEE(record[:size]) != binary.BigEndian.Uint32(record[size:]) {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrCorruptSnapshot)
+	}
+
+	return record[:size], nil
+}
@@ -0,0 +1,115 @@
+package dataloader
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"bytes"
+	"context"
+	"errors"
+)
+
+var _ = Describe("CacheSnapshot", func() {
+	It("restore resolved entries only", func() {
+		ctx := context.TODO()
+		source := NewInMemoryCache[string, *Thunk[string]]()
+
+		resolved := NewThunk[string]()
+		resolved.set(ctx, "bar")
+		failed := NewThunk[string]()
+		failed.error(ctx, errors.New("failed"))
+		source.Set(ctx, "foo", resolved)
+		source.Set(ctx, "failed", failed)
+		source.Set(ctx, "pending", NewThunk[string]())
+
+		buf := &bytes.Buffer{}
+		err := NewCacheSnapshot[string, string](source, NewGobSnapshotCodec[string, string]()).Snapshot(buf)
+		Expect(err).To(BeNil())
+
+		target := NewLRUCache[string, *Thunk[string]](10)
+		err = NewCacheSnapshot[string, string](target, NewGobSnapshotCodec[string, string]()).Restore(buf)
+		Expect(err).To(BeNil())
+
+		thunk, _ := target.Get(ctx, "foo")
+		Expect(thunk).NotTo(BeNil())
+		val, err := thunk.Get(ctx)
+		Expect(err).To(BeNil())
+		Expect(val).To(Equal("bar"))
+
+		for _, key := range []string{"failed", "pending"} {
+			thunk, _ := target.Get(ctx, key)
+			Expect(thunk).To(BeNil())
+		}
+	})
+
+	It("warm a new loader", func() {
+		ctx := context.TODO()
+		source := NewInMemoryCache[string, *Thunk[string]]()
+		warm := New[string, string, string](ctx, func(ctx context.Context, keys []string) []Result[string] {
+			result := make([]Result[string], len(keys))
+			for index, key := range keys {
+				result[index] = Result[string]{Value: "res:" + key}
+			}
+			return result
+		}, WithCacheMap[string, string, string](source))
+		_, err := warm.Load(ctx, "foo").Get(ctx)
+		Expect(err).To(BeNil())
+
+		buf := &bytes.Buffer{}
+		Expect(NewCacheSnapshot[string, string](source, NewGobSnapshotCodec[string, string]()).Snapshot(buf)).To(Succeed())
+
+		target := NewInMemoryCache[string, *Thunk[string]]()
+		Expect(NewCacheSnapshot[string, string](target, NewGobSnapshotCodec[string, string]()).Restore(buf)).To(Succeed())
+
+		loadCount := 0
+		loader := New[string, string, string](ctx, func(ctx context.Context, keys []string) []Result[string] {
+			loadCount += 1
+			return make([]Result[string], len(keys))
+		}, WithCacheMap[string, string, string](target))
+		val, err := loader.Load(ctx, "foo").Get(ctx)
+		Expect(err).To(BeNil())
+		Expect(val).To(Equal("res:foo"))
+		Expect(loadCount).To(Equal(0))
+	})
+
+	It("fail on cache map without range", func() {
+		err := NewCacheSnapshot[string, string](NewNoCache[string, *Thunk[string]](), NewGobSnapshotCodec[string, string]()).Snapshot(&bytes.Buffer{})
+		Expect(err).To(Equal(ErrUnrangeableCache))
+	})
+
+	It("reject corrupt snapshot without touching cache", func() {
+		ctx := context.TODO()
+		source := NewInMemoryCache[string, *Thunk[string]]()
+		for _, key := range []string{"foo", "bar"} {
+			thunk := NewThunk[string]()
+			thunk.set(ctx, key)
+			source.Set(ctx, key, thunk)
+		}
+
+		buf := &bytes.Buffer{}
+		Expect(NewCacheSnapshot[string, string](source, NewGobSnapshotCodec[string, string]()).Snapshot(buf)).To(Succeed())
+		data := buf.Bytes()
+
+		corruptions := map[string][]byte{
+			"empty":     {},
+			"header":    append([]byte("NOTASNAP"), data[8:]...),
+			"truncated": data[:len(data)-3],
+			"checksum":  append(append([]byte(nil), data[:len(data)-2]...), data[len(data)-2]^0xff, 0),
+			"length":    append(append([]byte(nil), data[:9]...), 0xff, 0xff, 0xff, 0xff, 0x7f),
+		}
+
+		for name, corrupt := range corruptions {
+			target := NewInMemoryCache[string, *Thunk[string]]()
+			err := NewCacheSnapshot[string, string](target, NewGobSnapshotCodec[string, string]()).Restore(bytes.NewReader(corrupt))
+			Expect(errors.Is(err, ErrCorruptSnapshot)).To(BeTrue(), name)
+			Expect(target.items).To(BeEmpty(), name)
+		}
+	})
+
+	It("reject unknown snapshot version", func() {
+		data := append([]byte(snapshotMagic), 99, 0)
+		target := NewInMemoryCache[string, *Thunk[string]]()
+		err := NewCacheSnapshot[string, string](target, NewGobSnapshotCodec[string, string]()).Restore(bytes.NewReader(data))
+		Expect(errors.Is(err, ErrUnsupportedSnapshotVersion)).To(BeTrue())
+	})
+})
@@ -0,0 +1,103 @@
+package dataloader
+
+import (
+	"context"
+	"time"
+)
+
+// Stats is a snapshot of the activity of a loader since it was created.
+type Stats struct {
+	// Loads is the number of Load calls, including the keys of LoadMany.
+	Loads uint64
+	// CacheHits and CacheMisses split the loads which reached the cache.
+	CacheHits   uint64
+	CacheMisses uint64
+	// Batches is the number of batch load function calls, and DispatchedKeys
+	// the number of keys passed to them.
+	Batches        uint64
+	DispatchedKeys uint64
+	// MeanBatchSize and MaxBatchSize describe the keys per batch load function
+	// call.
+	MeanBatchSize float64
+	MaxBatchSize  int
+	// PendingBatches is the number of batches waiting to be dispatched.
+	PendingBatches int
+	// InFlightBatches is the number of batches being loaded.
+	InFlightBatches int
+	// CacheSize is the number of cache entries, or -1 unless the cache map is
+	// a SizedCacheMap.
+	CacheSize int
+}
+
+// StatsProvider is implemented by every loader, whatever its type parameters,
+// for example to collect the stats of many loaders in a debug endpoint.
+type StatsProvider interface {
+	Stats() Stats
+}
+
+// Stats returns a snapshot of the loader's counters.
+func (l *loader[K, V, C]) Stats() Stats {
+	stats := <-l.stats
+	snapshot := *stats
+	l.stats <- stats
+
+	if snapshot.Batches != 0 {
+		snapshot.MeanBatchSize = float64(snapshot.DispatchedKeys) / float64(snapshot.Batches)
+	}
+
+	batches := <-l.batches
+	snapshot.PendingBatches = len(batches)
+	l.batches <- batches
+
+	snapshot.CacheSize = -1
+	cacheMap := <-l.cacheMap
+	if sized, ok := cacheMap.(SizedCacheMap[C, *Thunk[V]]); ok {
+		if size, err := sized.Len(context.Background()); err == nil {
+			snapshot.CacheSize = size
+		}
+	}
+	l.cacheMap <- cacheMap
+
+	return snapshot
+}
+
+// count updates the counters of the loader with fn.
+func (l *loader[K, V, C]) count(fn func(stats *Stats)) {
+	stats := <-l.stats
+	fn(stats)
+	l.stats <- stats
+}
+
+// PendingBatch describes a batch waiting to be dispatched.
+type PendingBatch struct {
+	Keys      []interface{}
+	CreatedAt time.Time
+	Priority  Priority
+	Partition string
+}
+
+// BatchInspector is implemented by every loader, whatever its type
+// parameters, to list its pending batches, for example to find stuck thunks.
+type BatchInspector interface {
+	PendingBatches() []PendingBatch
+}
+
+// PendingBatches returns the batches waiting to be dispatched, oldest first.
+func (l *loader[K, V, C]) PendingBatches() []PendingBatch {
+	batches := <-l.batches
+	pending := make([]PendingBatch, len(batches))
+	for index, batch := range batches {
+		keys := make([]interface{}, len(batch.keys))
+		for i, key := range batch.keys {
+			keys[i] = key
+		}
+		pending[index] = PendingBatch{
+			Keys:      keys,
+			CreatedAt: batch.createdAt,
+			Priority:  batch.priority,
+			Partition: batch.partition,
+		}
+	}
+	l.batches <- batches
+	return pending
+}
@@ -0,0 +1,74 @@
+package dataloader
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"context"
+	"time"
+)
+
+var _ = Describe("Stats", func() {
+	It("count loads, cache hits and batches", func() {
+		ctx := context.TODO()
+		loader := New[string, string, string](ctx, loadValues,
+			WithMaxBatchSize[string, string, string](2),
+			WithBatchScheduleFn[string, string, string](NewTimeWindowScheduler(1*time.Second)),
+		)
+
+		stats := loader.(StatsProvider)
+		thunks := loader.LoadMany(ctx, []string{"a", "b", "c", "a", "d", "e"})
+		Expect(stats.Stats().PendingBatches).To(Equal(3))
+		pending := loader.(BatchInspector).PendingBatches()
+		Expect(pending).To(HaveLen(3))
+		Expect(pending[0].Keys).To(Equal([]interface{}{"a", "b"}))
+		Expect(pending[0].Priority).To(Equal(PriorityNormal))
+		loader.Dispatch()
+		for _, thunk := range thunks {
+			_, err := thunk.Get(ctx)
+			Expect(err).To(BeNil())
+		}
+
+		Eventually(func() int { return stats.Stats().InFlightBatches }).Should(BeZero())
+		Expect(stats.Stats()).To(Equal(Stats{
+			Loads:          6,
+			CacheHits:      1,
+			CacheMisses:    5,
+			Batches:        3,
+			DispatchedKeys: 5,
+			MeanBatchSize:  5.0 / 3.0,
+			MaxBatchSize:   2,
+			CacheSize:      5,
+		}))
+	})
+
+	It("report in flight batches", func() {
+		ctx := context.TODO()
+		release := make(chan struct{})
+		batchLoadFn := func(ctx context.Context, keys []string) []Result[string] {
+			<-release
+			return loadValues(ctx, keys)
+		}
+
+		loader := New[string, string, string](ctx, batchLoadFn,
+			WithCacheMap[string, string, string](NewNoCache[string, *Thunk[string]]()),
+		)
+		stats := loader.(StatsProvider)
+		thunk := loader.Load(ctx, "foo")
+
+		Eventually(func() int { return stats.Stats().InFlightBatches }).Should(Equal(1))
+		Expect(stats.Stats().PendingBatches).To(BeZero())
+		Expect(stats.Stats().CacheSize).To(Equal(-1))
+
+		close(release)
+		_, err := thunk.Get(ctx)
+		Expect(err).To(BeNil())
+		Eventually(func() int { return stats.Stats().InFlightBatches }).Should(BeZero())
+	})
+
+	It("be provided by every loader", func() {
+		provider, ok := New[string, string, string](context.TODO(), loadValues).(StatsProvider)
+		Expect(ok).To(BeTrue())
+		Expect(provider.Stats().Loads).To(BeZero())
+	})
+})
@@ -2,13 +2,22 @@ package dataloader
 
 import (
 	"context"
+	"sync/atomic"
 )
 
 type Thunk[V any] struct {
 	pending chan bool
 	data    chan *thunkData[V]
+	// resolved is set once data holds a value, so peek never waits on data.
+	resolved int32
+	// waited holds a func() called by Get while the thunk is pending, so the
+	// batch holding it can be dispatched as soon as someone waits.
+	waited atomic.Value
 }
 
+// noWait replaces the waited func of a thunk once its batch is dispatched.
+var noWait = func() {}
+
 type thunkData[V any] struct {
 	value V
 	err   error
@@ -26,6 +35,16 @@ func NewThunk[V any]() *Thunk[V] {
 }
 
 func (t *Thunk[V]) Get(ctx context.Context) (V, error) {
+	if _, ok := t.peek(); !ok {
+		if waited, ok := t.waited.Load().(func()); ok {
+			waited()
+		}
+		if scope := scopeFromContext(ctx); scope != nil {
+			scope.wait()
+			defer scope.resume()
+		}
+	}
+
 	select {
 	case <-ctx.Done():
 		return *new(V), ctx.Err()
@@ -35,6 +54,18 @@ func (t *Thunk[V]) Get(ctx context.Context) (V, error) {
 	}
 }
 
+// peek returns the resolved data without waiting, or false if the thunk is
+// still pending.
+func (t *Thunk[V]) peek() (*thunkData[V], bool) {
+	if atomic.LoadInt32(&t.resolved) == 0 {
+		return nil, false
+	}
+
+	v := <-t.data
+	t.data <- v
+	return v, true
+}
+
 func (t *Thunk[V]) set(ctx context.Context, value V) (V, error) {
 	select {
 	case <-t.data:
@@ -42,6 +73,7 @@ func (t *Thunk[V]) set(ctx context.Context, value V) (V, error) {
 	}
 
 	t.data <- &thunkData[V]{value: value}
+	atomic.StoreInt32(&t.resolved, 1)
 
 	return t.Get(ctx)
 }
@@ -53,6 +85,7 @@ func (t *Thunk[V]) error(ctx context.Context, err error) (V, error) {
 	}
 
 	t.data <- &thunkData[V]{err: err}
+	atomic.StoreInt32(&t.resolved, 1)
 
 	return t.Get(ctx)
 }
@@ -276,4 +276,51 @@ var _ = Describe("Thunk", func() {
 
 		<-done
 	})
+
+	It("can peek without waiting", func() {
+		ctx := context.TODO()
+		thunk := NewThunk[string]()
+
+		_, ok := thunk.peek()
+		Expect(ok).To(BeFalse())
+
+		thunk.set(ctx, "foo")
+		data, ok := thunk.peek()
+		Expect(ok).To(BeTrue())
+		Expect(data.value).To(Equal("foo"))
+		Expect(data.err).To(BeNil())
+	})
+
+	It("return on cancel while other goroutines get the pending thunk", func() {
+		thunk := NewThunk[string]()
+		ctx, cancel := context.WithCancel(context.TODO())
+		cancel()
+
+		// Hold the pending token as a concurrent Get checking the thunk does.
+		pending := <-thunk.pending
+		done := make(chan struct{})
+		go func() {
+			defer GinkgoRecover()
+			defer close(done)
+			_, err := thunk.Get(ctx)
+			Expect(err).To(Equal(context.Canceled))
+		}()
+		Eventually(done).Should(BeClosed())
+		thunk.pending <- pending
+
+		wg := sync.WaitGroup{}
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					_, err := thunk.Get(ctx)
+					Expect(err).To(Equal(context.Canceled))
+				}
+			}()
+		}
+		wg.Wait()
+		thunk.set(context.TODO(), "foo")
+	})
 })
@@ -0,0 +1,155 @@
+package dataloader
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// BatchScheduler schedules batches without a goroutine per batch. Unlike a
+// BatchScheduleFn, Schedule is called synchronously when a batch is created and
+// must return without blocking; the scheduler calls callback later.
+type BatchScheduler interface {
+	Schedule(ctx context.Context, batch Batch, callback func())
+}
+
+// BatchWaker is implemented by BatchSchedulers polling their batches. The
+// loader calls Wake when a batch becomes full or is manually dispatched, so it
+// is dispatched without waiting for the next poll.
+type BatchWaker interface {
+	Wake()
+}
+
+// TickerScheduler is a BatchScheduler keeping every pending batch in one
+// registry, watched by a single goroutine which runs only while batches are
+// pending. On each tick it dispatches the batches older than the window and the
+// ones which are full or manually dispatched.
+type TickerScheduler struct {
+	window     time.Duration
+	resolution time.Duration
+	state      chan *tickerState
+	wake       chan struct{}
+}
+
+type tickerState struct {
+	entries []tickerEntry
+	running bool
+}
+
+type tickerEntry struct {
+	ctx      context.Context
+	batch    Batch
+	callback func()
+}
+
+var (
+	sharedTickerScheduler     *TickerScheduler
+	sharedTickerSchedulerOnce sync.Once
+)
+
+// NewTickerScheduler returns a scheduler dispatching batches window after they
+// were created, checking them every resolution.
+func NewTickerScheduler(window time.Duration, resolution time.Duration) *TickerScheduler {
+	s := &TickerScheduler{
+		window:     window,
+		resolution: resolution,
+		state:      make(chan *tickerState, 1),
+		wake:       make(chan struct{}, 1),
+	}
+	s.state <- &tickerState{}
+	return s
+}
+
+// SharedTickerScheduler returns the process wide TickerScheduler, with the
+// default 16ms window and a 1ms resolution.
+func SharedTickerScheduler() *TickerScheduler {
+	sharedTickerSchedulerOnce.Do(func() {
+		sharedTickerScheduler = NewTickerScheduler(16*time.Millisecond, 1*time.Millisecond)
+	})
+	return sharedTickerScheduler
+}
+
+func (s *TickerScheduler) Schedule(ctx context.Context, batch Batch, callback func()) {
+	state := <-s.state
+	state.entries = append(state.entries, tickerEntry{ctx: ctx, batch: batch, callback: callback})
+	start := !state.running
+	state.running = true
+	s.state <- state
+
+	if start {
+		go s.run(ClockFromContext(ctx))
+	}
+}
+
+// Wake runs a tick right away instead of at the next resolution.
+func (s *TickerScheduler) Wake() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Pending returns the number of batches waiting to be dispatched.
+func (s *TickerScheduler) Pending() int {
+	state := <-s.state
+	n := len(state.entries)
+	s.state <- state
+	return n
+}
+
+func (s *TickerScheduler) run(clock Clock) {
+	timer := clock.NewTimer(s.resolution)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C():
+		case <-s.wake:
+			timer.Stop()
+		}
+		if !s.tick(clock.Now()) {
+			return
+		}
+		timer.Reset(s.resolution)
+	}
+}
+
+// tick dispatches the ready batches and reports whether batches are still
+// pending. The loop stops once the registry is empty.
+func (s *TickerScheduler) tick(now time.Time) bool {
+	state := <-s.state
+	pending := state.entries[:0]
+	ready := []tickerEntry{}
+	for _, entry := range state.entries {
+		select {
+		case <-entry.ctx.Done():
+			continue
+		case <-entry.batch.Dispatch():
+			ready = append(ready, entry)
+			continue
+		case <-entry.batch.Full():
+			ready = append(ready, entry)
+			continue
+		default:
+		}
+
+		if now.Sub(entry.batch.CreatedAt()) >= s.window {
+			ready = append(ready, entry)
+		} else {
+			pending = append(pending, entry)
+		}
+	}
+	for index := len(pending); index < len(state.entries); index++ {
+		state.entries[index] = tickerEntry{}
+	}
+	state.entries = pending
+	state.running = len(pending) != 0
+	running := state.running
+	s.state <- state
+
+	for _, entry := range ready {
+		go entry.callback()
+	}
+
+	return running
+}
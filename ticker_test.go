@@ -0,0 +1,155 @@
+package dataloader
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gleak"
+
+	"context"
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func loadValues(ctx context.Context, keys []string) []Result[string] {
+	result := make([]Result[string], len(keys))
+	for index, key := range keys {
+		result[index] = Result[string]{Value: "res:" + key}
+	}
+	return result
+}
+
+var _ = Describe("TickerScheduler", func() {
+	AfterEach(func() {
+		Eventually(Goroutines).ShouldNot(HaveLeaked())
+	})
+
+	It("dispatch batches of many loaders from one goroutine", func() {
+		ctx := context.TODO()
+		scheduler := NewTickerScheduler(50*time.Millisecond, 1*time.Millisecond)
+		base := runtime.NumGoroutine()
+
+		thunks := []*Thunk[string]{}
+		for i := 0; i < 100; i++ {
+			loader := New[string, string, string](ctx, loadValues, WithBatchScheduler[string, string, string](scheduler))
+			thunks = append(thunks, loader.Load(ctx, fmt.Sprintf("key%d", i)))
+		}
+		Expect(scheduler.Pending()).To(Equal(100))
+		Expect(runtime.NumGoroutine() - base).To(BeNumerically("<=", 1))
+
+		for i, thunk := range thunks {
+			val, err := thunk.Get(ctx)
+			Expect(err).To(BeNil())
+			Expect(val).To(Equal(fmt.Sprintf("res:key%d", i)))
+		}
+		Eventually(scheduler.Pending).Should(Equal(0))
+	})
+
+	It("wait for the window before dispatching", func() {
+		ctx := context.TODO()
+		scheduler := NewTickerScheduler(100*time.Millisecond, 1*time.Millisecond)
+		loader := New[string, string, string](ctx, loadValues, WithBatchScheduler[string, string, string](scheduler))
+
+		start := time.Now()
+		thunks := loader.LoadMany(ctx, []string{"foo", "bar"})
+		for _, thunk := range thunks {
+			_, err := thunk.Get(ctx)
+			Expect(err).To(BeNil())
+		}
+		Expect(time.Since(start)).To(BeNumerically(">=", 100*time.Millisecond))
+	})
+
+	It("dispatch full and manually dispatched batches on next tick", func() {
+		ctx := context.TODO()
+		scheduler := NewTickerScheduler(1*time.Second, 1*time.Millisecond)
+		full := New[string, string, string](ctx, loadValues,
+			WithMaxBatchSize[string, string, string](1),
+			WithBatchScheduler[string, string, string](scheduler),
+		)
+		manual := New[string, string, string](ctx, loadValues, WithBatchScheduler[string, string, string](scheduler))
+
+		start := time.Now()
+		t1 := full.Load(ctx, "foo")
+		t2 := manual.Load(ctx, "bar")
+		manual.Dispatch()
+
+		for _, thunk := range []*Thunk[string]{t1, t2} {
+			_, err := thunk.Get(ctx)
+			Expect(err).To(BeNil())
+		}
+		Expect(time.Since(start)).To(BeNumerically("<", 500*time.Millisecond))
+	})
+
+	It("wake up for full and manually dispatched batches", func() {
+		ctx := context.TODO()
+		scheduler := NewTickerScheduler(10*time.Second, 1*time.Second)
+		full := New[string, string, string](ctx, loadValues,
+			WithMaxBatchSize[string, string, string](2),
+			WithBatchScheduler[string, string, string](scheduler),
+		)
+		manual := New[string, string, string](ctx, loadValues, WithBatchScheduler[string, string, string](scheduler))
+
+		start := time.Now()
+		thunks := full.LoadMany(ctx, []string{"foo", "bar"})
+		_, err := thunks[0].Get(ctx)
+		Expect(err).To(BeNil())
+		Expect(time.Since(start)).To(BeNumerically("<", 500*time.Millisecond))
+
+		start = time.Now()
+		thunk := manual.Load(ctx, "baz")
+		manual.Dispatch()
+		_, err = thunk.Get(ctx)
+		Expect(err).To(BeNil())
+		Expect(time.Since(start)).To(BeNumerically("<", 500*time.Millisecond))
+	})
+
+	It("drop batches of canceled loaders", func() {
+		ctx, cancel := context.WithCancel(context.TODO())
+		scheduler := NewTickerScheduler(1*time.Second, 1*time.Millisecond)
+		loader := New[string, string, string](ctx, loadValues, WithBatchScheduler[string, string, string](scheduler))
+
+		loader.Load(ctx, "foo")
+		cancel()
+		Eventually(scheduler.Pending).Should(Equal(0))
+	})
+
+	It("share one process wide scheduler", func() {
+		Expect(SharedTickerScheduler()).To(BeIdenticalTo(SharedTickerScheduler()))
+	})
+})
+
+func benchmarkScheduler(b *testing.B, options ...option[string, string, string]) {
+	ctx := context.TODO()
+	base := runtime.NumGoroutine()
+	peak := 0
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		loaders := make([]DataLoader[string, string, string], 100)
+		thunks := make([]*Thunk[string], 100)
+		for index := range loaders {
+			loaders[index] = New[string, string, string](ctx, loadValues, options...)
+			thunks[index] = loaders[index].Load(ctx, "foo")
+		}
+
+		if n := runtime.NumGoroutine() - base; n > peak {
+			peak = n
+		}
+
+		for index, loader := range loaders {
+			loader.Dispatch()
+			thunks[index].Get(ctx)
+		}
+	}
+	b.ReportMetric(float64(peak), "peak-goroutines")
+}
+
+func BenchmarkTimeWindowScheduler(b *testing.B) {
+	benchmarkScheduler(b, WithBatchScheduleFn[string, string, string](NewTimeWindowScheduler(16*time.Millisecond)))
+}
+
+func BenchmarkTickerScheduler(b *testing.B) {
+	benchmarkScheduler(b, WithBatchScheduler[string, string, string](NewTickerScheduler(16*time.Millisecond, 1*time.Millisecond)))
+}